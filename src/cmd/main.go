@@ -1,7 +1,10 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lfdelima3/Backend-Go-Bet/src/config"
@@ -14,16 +17,49 @@ func main() {
 	// Carrega as configurações
 	cfg := config.LoadConfig()
 
+	// Subcomando de migrações: migrate up|down [n]|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
 	// Inicializa o banco de dados
 	config.Connect()
 
+	// Não inicia com o esquema desatualizado
+	pending, err := config.PendingMigrations(config.DB)
+	if err != nil {
+		log.Fatalf("Erro ao verificar migrações: %v", err)
+	}
+	if len(pending) > 0 {
+		log.Fatalf("Existem %d migrações pendentes (a primeira é %04d_%s). Execute \"migrate up\" antes de iniciar o servidor",
+			len(pending), pending[0].Version, pending[0].Name)
+	}
+
 	// Inicializa o cache
 	cache := util.NewCache()
 
+	// Anula periodicamente as apostas de partidas adiadas além do prazo
+	go func() {
+		ticker := time.NewTicker(cfg.Betting.PostponementSweepInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			voided, err := controller.VoidExpiredPostponements(config.DB, cfg.Betting.PostponementVoidWindow)
+			if err != nil {
+				util.LogError("Erro ao anular apostas de partidas adiadas", err)
+				continue
+			}
+			if voided > 0 {
+				util.LogInfo(fmt.Sprintf("%d apostas de partidas adiadas foram anuladas", voided))
+			}
+		}
+	}()
+
 	// Configura o Gin
 	router := gin.Default()
 
 	// Configura os controllers
+	controller.SetBettingConfig(cfg.Betting)
 	promotionController := controller.NewPromotionController(config.DB)
 
 	// Configura as rotas
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/lfdelima3/Backend-Go-Bet/src/config"
+)
+
+// runMigrate executa o subcomando "migrate up|down [n]|status"
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		log.Fatal("Uso: migrate up | migrate down [passos] | migrate status")
+	}
+
+	config.Connect()
+
+	switch args[0] {
+	case "up":
+		applied, err := config.MigrateUp(config.DB)
+		for _, m := range applied {
+			fmt.Printf("aplicada  %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Erro ao aplicar migrações: %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Nenhuma migração pendente")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatalf("Número de passos inválido: %s", args[1])
+			}
+			steps = n
+		}
+		reverted, err := config.MigrateDown(config.DB, steps)
+		for _, m := range reverted {
+			fmt.Printf("desfeita  %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Erro ao desfazer migrações: %v", err)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("Nenhuma migração aplicada para desfazer")
+		}
+
+	case "status":
+		statuses, err := config.MigrationStatuses(config.DB)
+		if err != nil {
+			log.Fatalf("Erro ao consultar migrações: %v", err)
+		}
+		for _, s := range statuses {
+			if s.Applied {
+				fmt.Printf("aplicada  %04d_%s  (%s)\n", s.Version, s.Name, s.AppliedAt.Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Printf("pendente  %04d_%s\n", s.Version, s.Name)
+			}
+		}
+
+	default:
+		log.Fatalf("Subcomando desconhecido: %s (use up, down ou status)", args[0])
+	}
+}
@@ -22,6 +22,7 @@ type Config struct {
 	Database  DatabaseConfig
 	JWT       JWTConfig
 	RateLimit RateLimitConfig
+	Betting   BettingConfig
 }
 
 // ServerConfig contém as configurações do servidor
@@ -53,6 +54,24 @@ type RateLimitConfig struct {
 	Window   time.Duration
 }
 
+// BettingConfig contém as regras operacionais das apostas
+type BettingConfig struct {
+	PostponementVoidWindow    time.Duration
+	PostponementSweepInterval time.Duration
+	// OddsMargin é a margem padrão aplicada às cotações sugeridas (0.05 = 5%)
+	OddsMargin float64
+	// A cada RiskLiabilityStep de responsabilidade em uma opção, a cotação cai
+	// RiskOddsCut e o limite de aposta, partindo de RiskMaxStake, cai pela metade
+	RiskLiabilityStep float64
+	RiskOddsCut       float64
+	RiskMaxStake      float64
+	// RiskLiabilityLimit é a exposição que gera um alerta de risco
+	RiskLiabilityLimit float64
+	// DefaultMaxStake é o limite de aposta usado quando nenhum limite
+	// configurado se aplica; o fator do usuário incide sobre ele
+	DefaultMaxStake float64
+}
+
 // LoadConfig carrega todas as configurações da aplicação
 func LoadConfig() *Config {
 	config := &Config{
@@ -77,6 +96,16 @@ func LoadConfig() *Config {
 			Requests: getIntEnv("RATE_LIMIT_REQUESTS", 100),
 			Window:   getDurationEnv("RATE_LIMIT_WINDOW", time.Minute),
 		},
+		Betting: BettingConfig{
+			PostponementVoidWindow:    getDurationEnv("POSTPONEMENT_VOID_WINDOW", 48*time.Hour),
+			PostponementSweepInterval: getDurationEnv("POSTPONEMENT_SWEEP_INTERVAL", 10*time.Minute),
+			OddsMargin:                getFloatEnv("ODDS_MARGIN", 0.05),
+			RiskLiabilityStep:         getFloatEnv("RISK_LIABILITY_STEP", 5000),
+			RiskOddsCut:               getFloatEnv("RISK_ODDS_CUT", 0.05),
+			RiskMaxStake:              getFloatEnv("RISK_MAX_STAKE", 1000),
+			RiskLiabilityLimit:        getFloatEnv("RISK_LIABILITY_LIMIT", 20000),
+			DefaultMaxStake:           getFloatEnv("DEFAULT_MAX_STAKE", 5000),
+		},
 	}
 
 	// Validações de segurança
@@ -110,6 +139,16 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getFloatEnv retorna o valor decimal da variável de ambiente ou o valor padrão
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 // getDurationEnv retorna o valor de duração da variável de ambiente ou o valor padrão
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
@@ -120,7 +159,8 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
-// Connect estabelece a conexão com o banco de dados
+// Connect estabelece a conexão com o banco de dados. As tabelas não são
+// criadas aqui: use o comando "migrate up".
 func Connect() {
 	config := LoadConfig()
 
@@ -141,9 +181,9 @@ func Connect() {
 		log.Fatalf("Falha ao conectar ao banco de dados: %v", err)
 	}
 
-	// Criar as tabelas no banco de dados
-	if err := DB.AutoMigrate(&model.User{}, &model.Team{}, &model.Tournament{}, &model.Match{}, &model.MatchTeam{}, &model.MatchEvent{}, &model.MatchStatistics{}, &model.Promotion{}); err != nil {
-		log.Fatalf("Falha ao criar tabelas: %v", err)
+	// Usa o modelo TournamentTeam (com JoinedAt) como tabela de ligação entre torneios e times
+	if err := DB.SetupJoinTable(&model.Tournament{}, "Teams", &model.TournamentTeam{}); err != nil {
+		log.Fatalf("Falha ao configurar tabela de times do torneio: %v", err)
 	}
 
 	log.Println("Conexão com o banco de dados estabelecida com sucesso")
@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS bets;
+DROP TABLE IF EXISTS promotions;
+DROP TABLE IF EXISTS match_statistics;
+DROP TABLE IF EXISTS match_events;
+DROP TABLE IF EXISTS match_teams;
+DROP TABLE IF EXISTS matches;
+DROP TABLE IF EXISTS tournament_teams;
+DROP TABLE IF EXISTS tournaments;
+DROP TABLE IF EXISTS players;
+DROP TABLE IF EXISTS teams;
+DROP TABLE IF EXISTS users;
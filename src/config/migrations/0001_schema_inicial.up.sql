@@ -0,0 +1,195 @@
+-- Esquema inicial, equivalente ao que o AutoMigrate criava.
+-- Usa IF NOT EXISTS para que bancos já criados pelo AutoMigrate possam ser
+-- adotados. Nesses bancos as tabelas existentes são mantidas, então as colunas
+-- que não existiam no AutoMigrate são acrescentadas com ALTER TABLE logo após
+-- a criação de cada tabela.
+
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial PRIMARY KEY,
+    name text,
+    email text,
+    password text,
+    role text,
+    balance decimal,
+    status text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS teams (
+    id bigserial PRIMARY KEY,
+    name text,
+    country text,
+    city text,
+    founded_year bigint,
+    stadium text,
+    logo text,
+    website text,
+    status text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS players (
+    id bigserial PRIMARY KEY,
+    team_id bigint,
+    name text,
+    number bigint,
+    position text,
+    nationality text,
+    birth_date timestamptz,
+    height decimal,
+    weight decimal,
+    status text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS tournaments (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name text NOT NULL,
+    description text,
+    start_date timestamptz NOT NULL,
+    end_date timestamptz NOT NULL,
+    status varchar(20) NOT NULL DEFAULT 'pending'
+);
+CREATE INDEX IF NOT EXISTS idx_tournaments_deleted_at ON tournaments (deleted_at);
+
+CREATE TABLE IF NOT EXISTS tournament_teams (
+    tournament_id bigint,
+    team_id bigint,
+    joined_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tournament_id, team_id)
+);
+ALTER TABLE tournament_teams ADD COLUMN IF NOT EXISTS joined_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS matches (
+    id bigserial PRIMARY KEY,
+    tournament_id bigint,
+    home_team_id bigint,
+    away_team_id bigint,
+    start_time timestamptz,
+    end_time timestamptz,
+    status text,
+    status_reason text,
+    market_status text DEFAULT 'open',
+    home_score bigint,
+    away_score bigint,
+    stadium text,
+    referee text,
+    attendance bigint,
+    weather text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS status_reason text;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS market_status text DEFAULT 'open';
+
+CREATE TABLE IF NOT EXISTS match_teams (
+    id bigserial PRIMARY KEY,
+    match_id bigint,
+    team_id bigint,
+    is_home boolean,
+    formation text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS match_events (
+    id bigserial PRIMARY KEY,
+    match_id bigint,
+    event_type text,
+    team_id bigint,
+    player_id bigint,
+    minute bigint,
+    description text,
+    goal_type text,
+    card_type text,
+    foul_type text,
+    sub_in_player_id bigint,
+    sub_out_player_id bigint,
+    throw_in_side text,
+    corner_side text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS match_statistics (
+    id bigserial PRIMARY KEY,
+    match_id bigint,
+    home_team_id bigint,
+    away_team_id bigint,
+    home_possession decimal,
+    away_possession decimal,
+    home_shots bigint,
+    away_shots bigint,
+    home_shots_on_target bigint,
+    away_shots_on_target bigint,
+    home_corners bigint,
+    away_corners bigint,
+    home_fouls bigint,
+    away_fouls bigint,
+    home_yellow_cards bigint,
+    away_yellow_cards bigint,
+    home_red_cards bigint,
+    away_red_cards bigint,
+    home_offsides bigint,
+    away_offsides bigint,
+    home_passes bigint,
+    away_passes bigint,
+    home_pass_accuracy decimal,
+    away_pass_accuracy decimal,
+    home_saves bigint,
+    away_saves bigint,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id bigserial PRIMARY KEY,
+    name text,
+    description text,
+    type text,
+    value decimal,
+    min_bet decimal,
+    max_bet decimal,
+    start_date timestamptz,
+    end_date timestamptz,
+    is_active boolean,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS bets (
+    id bigserial PRIMARY KEY,
+    user_id bigint,
+    match_id bigint,
+    bet_type text,
+    amount decimal,
+    odds decimal,
+    status text,
+    result text,
+    payout decimal,
+    cashout_value decimal,
+    is_cashout boolean,
+    bonus_applied decimal,
+    promotion_id bigint,
+    bet_limit decimal,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id bigserial PRIMARY KEY,
+    user_id bigint,
+    type text,
+    title text,
+    message text,
+    is_read boolean,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_users_email;
+DROP INDEX IF EXISTS idx_players_team_id;
+DROP INDEX IF EXISTS idx_match_statistics_match_id;
+DROP INDEX IF EXISTS idx_match_events_match_id;
+DROP INDEX IF EXISTS idx_matches_away_team_id_start_time;
+DROP INDEX IF EXISTS idx_matches_home_team_id_start_time;
+DROP INDEX IF EXISTS idx_matches_tournament_id;
+DROP INDEX IF EXISTS idx_bets_match_id_status;
+DROP INDEX IF EXISTS idx_bets_user_id_created_at;
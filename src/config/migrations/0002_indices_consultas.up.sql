@@ -0,0 +1,23 @@
+-- Índices para as consultas mais frequentes, que o AutoMigrate nunca criou.
+CREATE INDEX IF NOT EXISTS idx_bets_user_id_created_at ON bets (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_bets_match_id_status ON bets (match_id, status);
+CREATE INDEX IF NOT EXISTS idx_matches_tournament_id ON matches (tournament_id);
+CREATE INDEX IF NOT EXISTS idx_matches_home_team_id_start_time ON matches (home_team_id, start_time);
+CREATE INDEX IF NOT EXISTS idx_matches_away_team_id_start_time ON matches (away_team_id, start_time);
+CREATE INDEX IF NOT EXISTS idx_match_events_match_id ON match_events (match_id);
+CREATE INDEX IF NOT EXISTS idx_match_statistics_match_id ON match_statistics (match_id);
+CREATE INDEX IF NOT EXISTS idx_players_team_id ON players (team_id);
+
+-- O AutoMigrate não impedia e-mails repetidos. Esses cadastros precisam ser
+-- unificados manualmente antes do índice único, pois envolvem saldo e apostas.
+DO $$
+DECLARE
+    duplicated text;
+BEGIN
+    SELECT string_agg(email, ', ') INTO duplicated
+    FROM (SELECT email FROM users WHERE email IS NOT NULL GROUP BY email HAVING COUNT(*) > 1) d;
+    IF duplicated IS NOT NULL THEN
+        RAISE EXCEPTION 'Usuários com e-mail repetido impedem o índice único em users.email: %', duplicated;
+    END IF;
+END $$;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
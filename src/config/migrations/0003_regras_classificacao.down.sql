@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_matches_tournament_id_status;
+
+ALTER TABLE tournaments
+    DROP COLUMN IF EXISTS tie_breakers,
+    DROP COLUMN IF EXISTS points_loss,
+    DROP COLUMN IF EXISTS points_draw,
+    DROP COLUMN IF EXISTS points_win;
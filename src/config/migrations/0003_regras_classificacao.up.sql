@@ -0,0 +1,7 @@
+ALTER TABLE tournaments
+    ADD COLUMN IF NOT EXISTS points_win bigint NOT NULL DEFAULT 3,
+    ADD COLUMN IF NOT EXISTS points_draw bigint NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS points_loss bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tie_breakers text NOT NULL DEFAULT 'goal_difference,goals_scored,head_to_head';
+
+CREATE INDEX IF NOT EXISTS idx_matches_tournament_id_status ON matches (tournament_id, status);
@@ -0,0 +1 @@
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS round bigint NOT NULL DEFAULT 0;
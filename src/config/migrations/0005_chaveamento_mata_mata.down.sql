@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_matches_bracket_tie_id;
+
+ALTER TABLE matches
+    DROP COLUMN IF EXISTS away_penalties,
+    DROP COLUMN IF EXISTS home_penalties,
+    DROP COLUMN IF EXISTS away_extra_time_score,
+    DROP COLUMN IF EXISTS home_extra_time_score,
+    DROP COLUMN IF EXISTS leg,
+    DROP COLUMN IF EXISTS bracket_tie_id;
+
+DROP TABLE IF EXISTS bracket_ties;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS bracket_ties (
+    id bigserial PRIMARY KEY,
+    tournament_id bigint NOT NULL,
+    round bigint NOT NULL,
+    position bigint NOT NULL,
+    home_team_id bigint,
+    away_team_id bigint,
+    legs bigint NOT NULL DEFAULT 1,
+    away_goals_rule boolean NOT NULL DEFAULT false,
+    first_leg_at timestamptz NOT NULL,
+    second_leg_at timestamptz,
+    match_duration bigint NOT NULL DEFAULT 120,
+    winner_team_id bigint,
+    decided_by text,
+    status text NOT NULL DEFAULT 'pending',
+    next_tie_id bigint,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_bracket_ties_tournament_id ON bracket_ties (tournament_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bracket_ties_tournament_round_position ON bracket_ties (tournament_id, round, position);
+
+-- Prorrogação, pênaltis e vínculo das partidas com o confronto do chaveamento
+ALTER TABLE matches
+    ADD COLUMN IF NOT EXISTS bracket_tie_id bigint,
+    ADD COLUMN IF NOT EXISTS leg bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS home_extra_time_score bigint,
+    ADD COLUMN IF NOT EXISTS away_extra_time_score bigint,
+    ADD COLUMN IF NOT EXISTS home_penalties bigint,
+    ADD COLUMN IF NOT EXISTS away_penalties bigint;
+CREATE INDEX IF NOT EXISTS idx_matches_bracket_tie_id ON matches (bracket_tie_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tournament_teams_team_id;
+
+ALTER TABLE tournaments
+    DROP COLUMN IF EXISTS registration_deadline,
+    DROP COLUMN IF EXISTS max_teams;
@@ -0,0 +1,5 @@
+ALTER TABLE tournaments
+    ADD COLUMN IF NOT EXISTS max_teams bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS registration_deadline timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_tournament_teams_team_id ON tournament_teams (team_id);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS match_lineup_players (
+    id bigserial PRIMARY KEY,
+    match_id bigint NOT NULL,
+    team_id bigint NOT NULL,
+    player_id bigint NOT NULL,
+    shirt_number bigint NOT NULL,
+    role text NOT NULL,
+    position text NOT NULL,
+    is_captain boolean NOT NULL DEFAULT false,
+    subbed_in_minute bigint,
+    subbed_out_minute bigint,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_match_lineup_players_match_id ON match_lineup_players (match_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_match_lineup_players_match_player ON match_lineup_players (match_id, player_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_match_lineup_players_match_team_shirt ON match_lineup_players (match_id, team_id, shirt_number);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS player_suspensions;
+
+ALTER TABLE tournaments
+    DROP COLUMN IF EXISTS red_card_suspension_matches,
+    DROP COLUMN IF EXISTS yellow_suspension_matches,
+    DROP COLUMN IF EXISTS yellow_cards_for_suspension;
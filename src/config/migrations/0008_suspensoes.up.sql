@@ -0,0 +1,22 @@
+ALTER TABLE tournaments
+    ADD COLUMN IF NOT EXISTS yellow_cards_for_suspension bigint NOT NULL DEFAULT 3,
+    ADD COLUMN IF NOT EXISTS yellow_suspension_matches bigint NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS red_card_suspension_matches bigint NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS player_suspensions (
+    id bigserial PRIMARY KEY,
+    player_id bigint NOT NULL,
+    team_id bigint NOT NULL,
+    tournament_id bigint NOT NULL,
+    match_id bigint NOT NULL,
+    event_id bigint NOT NULL,
+    reason text NOT NULL,
+    matches_total bigint NOT NULL,
+    matches_remaining bigint NOT NULL,
+    status text NOT NULL DEFAULT 'active',
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_player_suspensions_player_id ON player_suspensions (player_id);
+CREATE INDEX IF NOT EXISTS idx_player_suspensions_tournament_id ON player_suspensions (tournament_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_player_suspensions_event_id ON player_suspensions (event_id);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS player_spells (
+    id bigserial PRIMARY KEY,
+    player_id bigint NOT NULL,
+    team_id bigint NOT NULL,
+    start_date timestamptz,
+    end_date timestamptz,
+    from_team_id bigint,
+    fee decimal NOT NULL DEFAULT 0,
+    notes text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_player_spells_player_id ON player_spells (player_id);
+CREATE INDEX IF NOT EXISTS idx_player_spells_team_id ON player_spells (team_id);
+
+-- O time atual de cada jogador vira o vínculo original, sem data de início
+INSERT INTO player_spells (player_id, team_id, created_at, updated_at)
+SELECT p.id, p.team_id, now(), now()
+FROM players p
+WHERE NOT EXISTS (SELECT 1 FROM player_spells s WHERE s.player_id = p.id);
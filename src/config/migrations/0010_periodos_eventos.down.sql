@@ -0,0 +1,5 @@
+ALTER TABLE bets DROP COLUMN IF EXISTS selection;
+
+ALTER TABLE match_events
+    DROP COLUMN IF EXISTS added_minute,
+    DROP COLUMN IF EXISTS period;
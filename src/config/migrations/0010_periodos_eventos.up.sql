@@ -0,0 +1,13 @@
+ALTER TABLE match_events
+    ADD COLUMN IF NOT EXISTS period text NOT NULL DEFAULT 'first_half',
+    ADD COLUMN IF NOT EXISTS added_minute bigint NOT NULL DEFAULT 0;
+
+-- Eventos existentes recebem o período correspondente ao minuto
+UPDATE match_events SET period = CASE
+    WHEN minute <= 45 THEN 'first_half'
+    WHEN minute <= 90 THEN 'second_half'
+    WHEN minute <= 105 THEN 'extra_time_first'
+    ELSE 'extra_time_second'
+END;
+
+ALTER TABLE bets ADD COLUMN IF NOT EXISTS selection text;
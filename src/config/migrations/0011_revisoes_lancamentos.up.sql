@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS match_event_revisions (
+    id bigserial PRIMARY KEY,
+    event_id bigint NOT NULL,
+    match_id bigint NOT NULL,
+    action text NOT NULL,
+    before text,
+    after text,
+    reason text,
+    changed_by bigint,
+    bets_resettled bigint NOT NULL DEFAULT 0,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_match_event_revisions_event_id ON match_event_revisions (event_id);
+CREATE INDEX IF NOT EXISTS idx_match_event_revisions_match_id ON match_event_revisions (match_id);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    bet_id bigint,
+    type text NOT NULL,
+    amount decimal NOT NULL,
+    description text,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_user_id ON ledger_entries (user_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_bet_id ON ledger_entries (bet_id);
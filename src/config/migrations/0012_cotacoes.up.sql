@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS market_odds (
+    id bigserial PRIMARY KEY,
+    match_id bigint NOT NULL,
+    market text NOT NULL,
+    selection text NOT NULL,
+    line decimal,
+    probability decimal NOT NULL,
+    fair_odds decimal NOT NULL,
+    odds decimal NOT NULL,
+    margin decimal NOT NULL,
+    status text NOT NULL DEFAULT 'draft',
+    created_by bigint,
+    reviewed_by bigint,
+    reviewed_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_market_odds_match_id ON market_odds (match_id);
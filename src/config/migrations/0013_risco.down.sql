@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS risk_alerts;
+DROP TABLE IF EXISTS risk_positions;
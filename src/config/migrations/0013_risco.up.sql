@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS risk_positions (
+    id bigserial PRIMARY KEY,
+    match_id bigint NOT NULL,
+    market text NOT NULL,
+    selection text NOT NULL DEFAULT '',
+    bet_count bigint NOT NULL DEFAULT 0,
+    stakes decimal NOT NULL DEFAULT 0,
+    payouts decimal NOT NULL DEFAULT 0,
+    liability decimal NOT NULL DEFAULT 0,
+    level bigint NOT NULL DEFAULT 0,
+    odds_factor decimal NOT NULL DEFAULT 1,
+    max_stake decimal NOT NULL DEFAULT 0,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_risk_positions_match_id ON risk_positions (match_id);
+
+CREATE TABLE IF NOT EXISTS risk_alerts (
+    id bigserial PRIMARY KEY,
+    match_id bigint NOT NULL,
+    market text NOT NULL,
+    selection text NOT NULL DEFAULT '',
+    liability decimal NOT NULL,
+    liability_limit decimal NOT NULL,
+    status text NOT NULL DEFAULT 'open',
+    acknowledged_by bigint,
+    acknowledged_at timestamptz,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_risk_alerts_match_id ON risk_alerts (match_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stake_limits;
+
+ALTER TABLE users DROP COLUMN IF EXISTS stake_factor;
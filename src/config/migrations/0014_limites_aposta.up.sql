@@ -0,0 +1,13 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS stake_factor decimal NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS stake_limits (
+    id bigserial PRIMARY KEY,
+    tournament_id bigint,
+    market text,
+    max_stake decimal NOT NULL DEFAULT 0,
+    max_payout decimal NOT NULL DEFAULT 0,
+    description text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_stake_limits_tournament_id ON stake_limits (tournament_id);
@@ -0,0 +1,2 @@
+-- Linha das apostas de mercados com linha (2.5 gols, handicap -0.75, ...)
+ALTER TABLE bets ADD COLUMN IF NOT EXISTS line decimal;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS bet_selections (
+    id bigserial PRIMARY KEY,
+    bet_id bigint NOT NULL,
+    bet_type text NOT NULL,
+    selection text,
+    line decimal,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_bet_selections_bet_id ON bet_selections (bet_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS bet_combinations;
+DROP TABLE IF EXISTS system_bet_legs;
+DROP TABLE IF EXISTS system_bets;
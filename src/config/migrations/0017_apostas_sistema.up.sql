@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS system_bets (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    type text NOT NULL,
+    size bigint NOT NULL DEFAULT 0,
+    unit_stake decimal NOT NULL,
+    total_stake decimal NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    payout decimal NOT NULL DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_system_bets_user_id ON system_bets (user_id);
+
+CREATE TABLE IF NOT EXISTS system_bet_legs (
+    id bigserial PRIMARY KEY,
+    system_bet_id bigint NOT NULL,
+    position bigint NOT NULL,
+    match_id bigint NOT NULL,
+    bet_type text NOT NULL,
+    selection text,
+    line decimal,
+    odds decimal NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_system_bet_legs_system_bet_id ON system_bet_legs (system_bet_id);
+CREATE INDEX IF NOT EXISTS idx_system_bet_legs_match_id ON system_bet_legs (match_id);
+
+CREATE TABLE IF NOT EXISTS bet_combinations (
+    id bigserial PRIMARY KEY,
+    system_bet_id bigint NOT NULL,
+    legs text NOT NULL,
+    size bigint NOT NULL,
+    stake decimal NOT NULL,
+    odds decimal NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    payout decimal NOT NULL DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_bet_combinations_system_bet_id ON bet_combinations (system_bet_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS admin_audit_logs;
+DROP FUNCTION IF EXISTS admin_audit_logs_immutable();
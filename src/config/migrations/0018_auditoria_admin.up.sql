@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS admin_audit_logs (
+    id bigserial PRIMARY KEY,
+    admin_id bigint NOT NULL,
+    action text NOT NULL,
+    target_type text NOT NULL,
+    target_id bigint NOT NULL,
+    user_id bigint NOT NULL,
+    reason_code text NOT NULL,
+    notes text,
+    amount decimal NOT NULL DEFAULT 0,
+    before text,
+    after text,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_admin_id ON admin_audit_logs (admin_id);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_user_id ON admin_audit_logs (user_id);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_target ON admin_audit_logs (target_type, target_id);
+
+-- A trilha de auditoria só aceita inserções
+CREATE OR REPLACE FUNCTION admin_audit_logs_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'admin_audit_logs não pode ser alterada';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS admin_audit_logs_immutable ON admin_audit_logs;
+CREATE TRIGGER admin_audit_logs_immutable
+    BEFORE UPDATE OR DELETE ON admin_audit_logs
+    FOR EACH ROW EXECUTE FUNCTION admin_audit_logs_immutable();
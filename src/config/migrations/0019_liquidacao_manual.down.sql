@@ -0,0 +1 @@
+ALTER TABLE bets DROP COLUMN IF EXISTS settled_manually;
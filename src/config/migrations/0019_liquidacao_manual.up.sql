@@ -0,0 +1,2 @@
+-- Apostas liquidadas ou anuladas pelo suporte não são reliquidadas pelas correções da partida
+ALTER TABLE bets ADD COLUMN IF NOT EXISTS settled_manually boolean NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bets_user_id_settled_at;
+ALTER TABLE bets DROP COLUMN IF EXISTS settled_at;
@@ -0,0 +1,6 @@
+-- Data da primeira liquidação da aposta, usada nos relatórios por período.
+-- Reliquidações e intervenções do suporte não a alteram.
+ALTER TABLE bets ADD COLUMN IF NOT EXISTS settled_at timestamptz;
+UPDATE bets SET settled_at = updated_at
+WHERE settled_at IS NULL AND status IN ('won', 'lost', 'half_won', 'half_lost', 'push', 'void');
+CREATE INDEX IF NOT EXISTS idx_bets_user_id_settled_at ON bets (user_id, settled_at);
@@ -0,0 +1 @@
+ALTER TABLE system_bets DROP COLUMN IF EXISTS settled_at;
@@ -0,0 +1,4 @@
+-- Data do fechamento das apostas de sistema, usada nos relatórios financeiros
+ALTER TABLE system_bets ADD COLUMN IF NOT EXISTS settled_at timestamptz;
+UPDATE system_bets SET settled_at = updated_at
+WHERE settled_at IS NULL AND status IN ('won', 'lost', 'void');
@@ -0,0 +1 @@
+ALTER TABLE bracket_ties DROP COLUMN IF EXISTS decision_reason;
@@ -0,0 +1,2 @@
+-- Justificativa do vencedor definido pela organização em confrontos sem resultado
+ALTER TABLE bracket_ties ADD COLUMN IF NOT EXISTS decision_reason text;
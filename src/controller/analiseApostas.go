@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+)
+
+// wonStatuses são as situações que contam como acerto na taxa de acerto
+var wonStatuses = []string{model.BetStatusWon, model.BetStatusHalfWon}
+
+type AnalyticsController struct {
+	DB    *gorm.DB
+	Cache util.Cache
+}
+
+func NewAnalyticsController(db *gorm.DB, cache util.Cache) *AnalyticsController {
+	return &AnalyticsController{DB: db, Cache: cache}
+}
+
+func analyticsCacheKey(userID uint, interval string) string {
+	return fmt.Sprintf("%s%d:%s", util.AnalyticsCacheKey, userID, interval)
+}
+
+// analyticsIntervals são os intervalos da curva de lucro, um por chave de cache
+var analyticsIntervals = []string{"day", "week", "month"}
+
+// analyticsCache é o cache do painel de desempenho, usado para descartá-lo
+// nos pontos em que as apostas mudam de situação. É definido por
+// SetAnalyticsCache; sem ele, nada é descartado.
+var analyticsCache util.Cache
+
+// SetAnalyticsCache define o cache do painel de desempenho
+func SetAnalyticsCache(cache util.Cache) {
+	analyticsCache = cache
+}
+
+// invalidateUserAnalytics descarta o painel de desempenho dos usuários. Deve
+// ser chamada depois do commit das mudanças nas apostas.
+func invalidateUserAnalytics(userIDs ...uint) {
+	if analyticsCache == nil {
+		return
+	}
+	for _, userID := range userIDs {
+		for _, interval := range analyticsIntervals {
+			if err := analyticsCache.Delete(analyticsCacheKey(userID, interval)); err != nil {
+				util.LogError("Erro ao invalidar desempenho em cache", err)
+			}
+		}
+	}
+}
+
+// invalidateMatchAnalytics descarta o painel de desempenho de quem apostou na
+// partida, depois de a liquidação ou a anulação das apostas ser gravada
+func invalidateMatchAnalytics(db *gorm.DB, matchID uint) {
+	if analyticsCache == nil {
+		return
+	}
+	var userIDs []uint
+	if err := db.Model(&model.Bet{}).Where("match_id = ?", matchID).Distinct().Pluck("user_id", &userIDs).Error; err != nil {
+		util.LogError("Erro ao buscar apostadores da partida", err)
+		return
+	}
+	invalidateUserAnalytics(userIDs...)
+}
+
+// percent calcula a razão em percentual, com duas casas
+func percent(part, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(part/total*10000) / 100
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// computeUserAnalytics agrega as apostas do usuário por tipo e por período de
+// liquidação. Apostas canceladas e anuladas ficam de fora.
+func computeUserAnalytics(db *gorm.DB, userID uint, interval string) (model.UserAnalyticsResponse, error) {
+	response := model.UserAnalyticsResponse{
+		UserID:      userID,
+		Interval:    interval,
+		ByBetType:   []model.BetTypeAnalytics{},
+		ProfitCurve: []model.ProfitPoint{},
+		GeneratedAt: time.Now(),
+	}
+
+	var rows []struct {
+		BetType       string
+		Bets          int
+		SettledBets   int
+		WonBets       int
+		PendingBets   int
+		PendingStaked float64
+		Staked        float64
+		Returned      float64
+		SumOdds       float64
+	}
+	if err := db.Model(&model.Bet{}).
+		Select(`bet_type,
+			COUNT(*) AS bets,
+			COUNT(CASE WHEN status IN @settled THEN 1 END) AS settled_bets,
+			COUNT(CASE WHEN status IN @won THEN 1 END) AS won_bets,
+			COUNT(CASE WHEN status = @pending THEN 1 END) AS pending_bets,
+			COALESCE(SUM(CASE WHEN status = @pending THEN amount END), 0) AS pending_staked,
+			COALESCE(SUM(CASE WHEN status IN @settled THEN amount END), 0) AS staked,
+			COALESCE(SUM(CASE WHEN status IN @settled THEN payout END), 0) AS returned,
+			COALESCE(SUM(odds), 0) AS sum_odds`,
+			map[string]interface{}{
+				"settled": settledStatuses,
+				"won":     wonStatuses,
+				"pending": model.BetStatusPending,
+			}).
+		Where("user_id = ? AND status NOT IN ?", userID, []string{model.BetStatusCancelled, model.BetStatusVoid}).
+		Group("bet_type").Order("bet_type").
+		Scan(&rows).Error; err != nil {
+		return response, err
+	}
+
+	sumOdds := 0.0
+	for _, r := range rows {
+		response.ByBetType = append(response.ByBetType, model.BetTypeAnalytics{
+			BetType:       r.BetType,
+			Bets:          r.Bets,
+			SettledBets:   r.SettledBets,
+			WonBets:       r.WonBets,
+			PendingBets:   r.PendingBets,
+			PendingStaked: round2(r.PendingStaked),
+			Staked:        round2(r.Staked),
+			Returned:      round2(r.Returned),
+			ProfitLoss:    round2(r.Returned - r.Staked),
+			ROI:           percent(r.Returned-r.Staked, r.Staked),
+			StrikeRate:    percent(float64(r.WonBets), float64(r.SettledBets)),
+			AverageOdds:   round2(r.SumOdds / float64(r.Bets)),
+		})
+		response.Bets += r.Bets
+		response.SettledBets += r.SettledBets
+		response.WonBets += r.WonBets
+		response.PendingBets += r.PendingBets
+		response.PendingStaked += r.PendingStaked
+		response.TotalStaked += r.Staked
+		response.TotalReturned += r.Returned
+		sumOdds += r.SumOdds
+	}
+	response.PendingStaked = round2(response.PendingStaked)
+	response.TotalStaked = round2(response.TotalStaked)
+	response.TotalReturned = round2(response.TotalReturned)
+	response.ProfitLoss = round2(response.TotalReturned - response.TotalStaked)
+	response.ROI = percent(response.ProfitLoss, response.TotalStaked)
+	response.StrikeRate = percent(float64(response.WonBets), float64(response.SettledBets))
+	if response.Bets > 0 {
+		response.AverageOdds = round2(sumOdds / float64(response.Bets))
+	}
+
+	// A curva usa a data da primeira liquidação, que não muda com reliquidações
+	if err := db.Model(&model.Bet{}).
+		Select("date_trunc(?, settled_at) AS period, COUNT(*) AS bets, SUM(amount) AS staked, SUM(payout) AS returned", interval).
+		Where("user_id = ? AND status IN ?", userID, settledStatuses).
+		Group("period").Order("period").
+		Scan(&response.ProfitCurve).Error; err != nil {
+		return response, err
+	}
+	cumulative := 0.0
+	for i := range response.ProfitCurve {
+		p := &response.ProfitCurve[i]
+		p.Staked = round2(p.Staked)
+		p.Returned = round2(p.Returned)
+		p.ProfitLoss = round2(p.Returned - p.Staked)
+		cumulative += p.ProfitLoss
+		p.Cumulative = round2(cumulative)
+	}
+
+	return response, nil
+}
+
+// GetMyAnalytics retorna o painel de desempenho do usuário autenticado:
+// totais apostados e retornados, lucro, ROI, taxa de acerto e cotação média
+// por tipo de aposta e a curva de lucro por dia, semana ou mês (interval).
+// O resultado fica em cache por usuário.
+func (c *AnalyticsController) GetMyAnalytics(ctx *gin.Context) {
+	interval := ctx.DefaultQuery("interval", "day")
+	if interval != "day" && interval != "week" && interval != "month" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Intervalo inválido, use day, week ou month"})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	key := analyticsCacheKey(userID, interval)
+
+	var response model.UserAnalyticsResponse
+	if err := c.Cache.Get(key, &response); err == nil {
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+
+	response, err := computeUserAnalytics(c.DB, userID, interval)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular desempenho", "details": err.Error()})
+		return
+	}
+
+	if err := c.Cache.Set(key, response, util.AnalyticsCacheExpiry); err != nil {
+		util.LogError("Erro ao salvar desempenho em cache", err)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
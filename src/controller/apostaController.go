@@ -1,6 +1,9 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,8 +12,13 @@ import (
 	"github.com/lfdelima3/Backend-Go-Bet/src/config"
 	"github.com/lfdelima3/Backend-Go-Bet/src/model"
 	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// maxBetLine é o maior valor absoluto aceito na linha de uma aposta
+const maxBetLine = 50.0
+
 // CreateBet cria uma nova aposta
 func CreateBet(c *gin.Context) {
 	var betCreate model.BetCreate
@@ -27,6 +35,12 @@ func CreateBet(c *gin.Context) {
 		return
 	}
 
+	// Mercados com mais de uma opção exigem o palpite
+	if err := validateBetSelection(betCreate.BetType, betCreate.Selection, betCreate.Line); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Verifica se o usuário existe
 	var user model.User
 	if err := config.DB.First(&user, userID).Error; err != nil {
@@ -34,12 +48,6 @@ func CreateBet(c *gin.Context) {
 		return
 	}
 
-	// Verifica se o usuário tem saldo suficiente
-	if user.Balance < betCreate.Amount {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Saldo insuficiente para realizar a aposta"})
-		return
-	}
-
 	// Verifica se a partida existe e está disponível para apostas
 	var match model.Match
 	if err := config.DB.First(&match, betCreate.MatchID).Error; err != nil {
@@ -47,7 +55,7 @@ func CreateBet(c *gin.Context) {
 		return
 	}
 
-	if match.Status != "scheduled" {
+	if match.Status != model.MatchStatusScheduled || match.MarketStatus == model.MarketStatusSuspended || match.MarketStatus == model.MarketStatusClosed {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "A partida não está disponível para apostas"})
 		return
 	}
@@ -64,26 +72,94 @@ func CreateBet(c *gin.Context) {
 		return
 	}
 
+	// Limites de aposta e de prêmio ajustados pelo fator do usuário
+	market, _ := betMarket(betCreate.BetType, betCreate.Selection)
+	maxStake, limited, err := maxAllowedStake(config.DB, user, match, market, betCreate.Odds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar limites de aposta", "details": err.Error()})
+		return
+	}
+	if limited && betCreate.Amount > maxStake {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     fmt.Sprintf("O valor máximo permitido para esta aposta é R$ %.2f", maxStake),
+			"max_stake": maxStake,
+		})
+		return
+	}
+
+	// Cotação publicada e limite de aposta conforme a exposição da casa
+	message, details, err := checkBetRisk(config.DB, betCreate.MatchID, betCreate.BetType, betCreate.Selection, betCreate.Line, betCreate.Amount, betCreate.Odds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar risco da aposta", "details": err.Error()})
+		return
+	}
+	if message != "" {
+		response := gin.H{"error": message}
+		for k, v := range details {
+			response[k] = v
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+
+	// O bônus da promoção cobre parte do valor apostado; só o restante sai do saldo
+	bonus := 0.0
+	if betCreate.PromotionID != nil {
+		var message string
+		bonus, message, err = betPromotionBonus(config.DB, userID, *betCreate.PromotionID, betCreate.Amount)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar promoção", "details": err.Error()})
+			return
+		}
+		if message != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": message})
+			return
+		}
+	}
+
+	// Verifica se o usuário tem saldo suficiente
+	if user.Balance < betCreate.Amount-bonus {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Saldo insuficiente para realizar a aposta"})
+		return
+	}
+
 	// Cria a aposta
 	bet := model.Bet{
-		UserID:    userID,
-		MatchID:   betCreate.MatchID,
-		BetType:   betCreate.BetType,
-		Amount:    betCreate.Amount,
-		Odds:      betCreate.Odds,
-		Status:    "pending",
-		CreatedAt: time.Now(),
+		UserID:       userID,
+		MatchID:      betCreate.MatchID,
+		BetType:      betCreate.BetType,
+		Amount:       betCreate.Amount,
+		Odds:         betCreate.Odds,
+		Selection:    betCreate.Selection,
+		Line:         betCreate.Line,
+		BonusApplied: bonus,
+		PromotionID:  betCreate.PromotionID,
+		BetLimit:     maxStake,
+		Status:       "pending",
+		CreatedAt:    time.Now(),
 	}
 
 	// Inicia uma transação
 	tx := config.DB.Begin()
 
-	// Atualiza o saldo do usuário
-	user.Balance -= betCreate.Amount
-	if err := tx.Save(&user).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar saldo do usuário", "details": err.Error()})
-		return
+	// Com promoção, o usuário é bloqueado e o uso do bônus conferido de novo,
+	// para que duas apostas simultâneas não usem o mesmo bônus
+	if bet.PromotionID != nil {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&model.User{}, userID).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar promoção", "details": err.Error()})
+			return
+		}
+		_, message, err := betPromotionBonus(tx, userID, *bet.PromotionID, bet.Amount)
+		if err != nil || message != "" {
+			tx.Rollback()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar promoção", "details": err.Error()})
+				return
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": message})
+			return
+		}
 	}
 
 	// Cria a aposta
@@ -93,29 +169,163 @@ func CreateBet(c *gin.Context) {
 		return
 	}
 
+	// Debita o saldo do usuário só se ele ainda cobrir o valor: só a coluna do
+	// saldo é alterada, sem sobrescrever o fator e os créditos gravados por
+	// outras requisições
+	if err := debitBalance(tx, userID, &bet.ID, model.LedgerTypeBetStake, bet.Amount-bet.BonusApplied,
+		fmt.Sprintf("Aposta #%d na partida #%d", bet.ID, bet.MatchID)); err != nil {
+		tx.Rollback()
+		if errors.Is(err, errInsufficientBalance) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Saldo insuficiente para realizar a aposta"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar saldo do usuário", "details": err.Error()})
+		return
+	}
+
+	if err := refreshRiskPositions(tx, bet.MatchID); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar exposição da partida", "details": err.Error()})
+		return
+	}
+
 	// Commit da transação
 	if err := tx.Commit().Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao finalizar transação", "details": err.Error()})
 		return
 	}
+	invalidateUserAnalytics(bet.UserID)
 
 	response := model.BetResponse{
-		ID:        bet.ID,
-		UserID:    bet.UserID,
-		MatchID:   bet.MatchID,
-		BetType:   bet.BetType,
-		Amount:    bet.Amount,
-		Odds:      bet.Odds,
-		Status:    bet.Status,
-		Result:    bet.Result,
-		Payout:    bet.Payout,
-		CreatedAt: bet.CreatedAt,
-		UpdatedAt: bet.UpdatedAt,
+		ID:           bet.ID,
+		UserID:       bet.UserID,
+		MatchID:      bet.MatchID,
+		BetType:      bet.BetType,
+		Amount:       bet.Amount,
+		Odds:         bet.Odds,
+		Selection:    bet.Selection,
+		Line:         bet.Line,
+		Status:       bet.Status,
+		Result:       bet.Result,
+		Payout:       bet.Payout,
+		BonusApplied: bet.BonusApplied,
+		PromotionID:  bet.PromotionID,
+		BetLimit:     bet.BetLimit,
+		CreatedAt:    bet.CreatedAt,
+		UpdatedAt:    bet.UpdatedAt,
 	}
 
 	c.JSON(http.StatusCreated, response)
 }
 
+// betPromotionBonus calcula o bônus que uma promoção de aposta (bet_bonus)
+// cobre no valor apostado: o valor da promoção, limitado ao da aposta. Cada
+// promoção vale uma vez por usuário; apostas canceladas liberam o bônus. O
+// segundo retorno explica por que a promoção não se aplica.
+func betPromotionBonus(db *gorm.DB, userID, promotionID uint, amount float64) (float64, string, error) {
+	var promotion model.Promotion
+	if err := db.First(&promotion, promotionID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, "Promoção não encontrada", nil
+		}
+		return 0, "", err
+	}
+
+	now := time.Now()
+	if !promotion.IsActive || now.Before(promotion.StartDate) || now.After(promotion.EndDate) {
+		return 0, "Esta promoção não está ativa", nil
+	}
+	if promotion.Type != model.PromotionTypeBetBonus {
+		return 0, "Esta promoção não se aplica a apostas", nil
+	}
+	if amount < promotion.MinBet || (promotion.MaxBet > 0 && amount > promotion.MaxBet) {
+		return 0, fmt.Sprintf("O valor da aposta para esta promoção deve estar entre R$ %.2f e R$ %.2f", promotion.MinBet, promotion.MaxBet), nil
+	}
+
+	var used int64
+	if err := db.Model(&model.Bet{}).
+		Where("user_id = ? AND promotion_id = ? AND status <> ?", userID, promotionID, model.BetStatusCancelled).
+		Count(&used).Error; err != nil {
+		return 0, "", err
+	}
+	if used > 0 {
+		return 0, "O bônus desta promoção já foi usado", nil
+	}
+
+	return math.Round(math.Min(promotion.Value, amount)*100) / 100, "", nil
+}
+
+// validateBetSelection verifica se o palpite e a linha são compatíveis com o
+// tipo de aposta
+func validateBetSelection(betType, selection string, line *float64) error {
+	switch betType {
+	case model.BetTypeFirstGoal:
+		if selection != model.SelectionHome && selection != model.SelectionAway && selection != model.SelectionNone {
+			return fmt.Errorf("o palpite de primeiro gol deve ser home, away ou none")
+		}
+	case model.BetTypeHalfTimeResult:
+		if selection != model.SelectionHome && selection != model.SelectionDraw && selection != model.SelectionAway {
+			return fmt.Errorf("o palpite do resultado no intervalo deve ser home, draw ou away")
+		}
+	case model.BetTypeOverUnder, model.BetTypeTotalGoals, model.BetTypeCorners, model.BetTypeCards:
+		if selection != model.SelectionOver && selection != model.SelectionUnder {
+			return fmt.Errorf("o palpite deste mercado deve ser over ou under")
+		}
+		if err := validateLine(line); err != nil {
+			return err
+		}
+		if *line < 0 {
+			return fmt.Errorf("a linha deste mercado não pode ser negativa")
+		}
+		return nil
+	case model.BetTypeAsianHandicap:
+		if selection != model.SelectionHome && selection != model.SelectionAway {
+			return fmt.Errorf("o palpite do handicap asiático deve ser home ou away")
+		}
+		return validateLine(line)
+	case model.BetTypeExactScore:
+		if selection == model.SelectionOtherScore {
+			break
+		}
+		home, away, ok := parseExactScore(selection)
+		if !ok || home > maxExactScoreGoals || away > maxExactScoreGoals {
+			return fmt.Errorf("o palpite de placar exato deve ser um placar de até %d gols por time, como 2-1, ou other", maxExactScoreGoals)
+		}
+	default:
+		if selection != "" {
+			return fmt.Errorf("este tipo de aposta não aceita palpite")
+		}
+	}
+	if line != nil {
+		return fmt.Errorf("este tipo de aposta não aceita linha")
+	}
+	return nil
+}
+
+// validateLine exige uma linha em múltiplos de 0,25 (linhas inteiras, de
+// meio gol e de quarto de gol)
+func validateLine(line *float64) error {
+	if line == nil {
+		return fmt.Errorf("informe a linha da aposta")
+	}
+	if math.Abs(*line) > maxBetLine || math.Mod(*line*4, 1) != 0 {
+		return fmt.Errorf("a linha deve ser múltipla de 0.25 e estar entre -%g e %g", maxBetLine, maxBetLine)
+	}
+	return nil
+}
+
+// parseExactScore lê o palpite de placar exato no formato "mandante-visitante"
+func parseExactScore(selection string) (int, int, bool) {
+	var home, away int
+	if n, err := fmt.Sscanf(selection, "%d-%d", &home, &away); err != nil || n != 2 || home < 0 || away < 0 {
+		return 0, 0, false
+	}
+	if fmt.Sprintf("%d-%d", home, away) != selection {
+		return 0, 0, false
+	}
+	return home, away, true
+}
+
 // ListUserBets retorna todas as apostas do usuário
 func ListUserBets(c *gin.Context) {
 	// Paginação
@@ -123,44 +333,17 @@ func ListUserBets(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	offset := (page - 1) * limit
 
-	// Filtros
-	status := c.Query("status")
-	matchID := c.Query("match_id")
-	betType := c.Query("bet_type")
-	startDate := c.Query("start_date")
-	endDate := c.Query("end_date")
+	// Filtros; datas inválidas são ignoradas na listagem
+	filters, _ := parseBetFilters(c)
 
 	userID := c.GetUint("user_id")
-	query := config.DB.Model(&model.Bet{}).Where("user_id = ?", userID)
-
-	// Aplica filtros
-	if status != "" {
-		query = query.Where("status = ?", status)
-	}
-	if matchID != "" {
-		query = query.Where("match_id = ?", matchID)
-	}
-	if betType != "" {
-		query = query.Where("bet_type = ?", betType)
-	}
-	if startDate != "" {
-		start, err := time.Parse("2006-01-02", startDate)
-		if err == nil {
-			query = query.Where("created_at >= ?", start)
-		}
-	}
-	if endDate != "" {
-		end, err := time.Parse("2006-01-02", endDate)
-		if err == nil {
-			query = query.Where("created_at <= ?", end)
-		}
-	}
+	query := filters.apply(config.DB.Model(&model.Bet{}).Where("user_id = ?", userID))
 
 	var total int64
 	query.Count(&total)
 
 	var bets []model.Bet
-	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&bets).Error; err != nil {
+	if err := query.Preload("Selections").Offset(offset).Limit(limit).Order("created_at DESC").Find(&bets).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar apostas", "details": err.Error()})
 		return
 	}
@@ -168,17 +351,20 @@ func ListUserBets(c *gin.Context) {
 	var response []model.BetResponse
 	for _, bet := range bets {
 		response = append(response, model.BetResponse{
-			ID:        bet.ID,
-			UserID:    bet.UserID,
-			MatchID:   bet.MatchID,
-			BetType:   bet.BetType,
-			Amount:    bet.Amount,
-			Odds:      bet.Odds,
-			Status:    bet.Status,
-			Result:    bet.Result,
-			Payout:    bet.Payout,
-			CreatedAt: bet.CreatedAt,
-			UpdatedAt: bet.UpdatedAt,
+			ID:         bet.ID,
+			UserID:     bet.UserID,
+			MatchID:    bet.MatchID,
+			BetType:    bet.BetType,
+			Amount:     bet.Amount,
+			Odds:       bet.Odds,
+			Selection:  bet.Selection,
+			Line:       bet.Line,
+			Status:     bet.Status,
+			Result:     bet.Result,
+			Payout:     bet.Payout,
+			Selections: bet.Selections,
+			CreatedAt:  bet.CreatedAt,
+			UpdatedAt:  bet.UpdatedAt,
 		})
 	}
 
@@ -199,23 +385,26 @@ func GetBet(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
 	var bet model.Bet
-	if err := config.DB.Where("id = ? AND user_id = ?", id, userID).First(&bet).Error; err != nil {
+	if err := config.DB.Preload("Selections").Where("id = ? AND user_id = ?", id, userID).First(&bet).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Aposta não encontrada"})
 		return
 	}
 
 	response := model.BetResponse{
-		ID:        bet.ID,
-		UserID:    bet.UserID,
-		MatchID:   bet.MatchID,
-		BetType:   bet.BetType,
-		Amount:    bet.Amount,
-		Odds:      bet.Odds,
-		Status:    bet.Status,
-		Result:    bet.Result,
-		Payout:    bet.Payout,
-		CreatedAt: bet.CreatedAt,
-		UpdatedAt: bet.UpdatedAt,
+		ID:         bet.ID,
+		UserID:     bet.UserID,
+		MatchID:    bet.MatchID,
+		BetType:    bet.BetType,
+		Amount:     bet.Amount,
+		Odds:       bet.Odds,
+		Selection:  bet.Selection,
+		Line:       bet.Line,
+		Status:     bet.Status,
+		Result:     bet.Result,
+		Payout:     bet.Payout,
+		Selections: bet.Selections,
+		CreatedAt:  bet.CreatedAt,
+		UpdatedAt:  bet.UpdatedAt,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -253,26 +442,32 @@ func CancelBet(c *gin.Context) {
 	// Inicia uma transação
 	tx := config.DB.Begin()
 
-	// Atualiza o saldo do usuário
-	var user model.User
-	if err := tx.First(&user, userID).Error; err != nil {
+	// Cancela a aposta só se ela ainda estiver pendente, para que duas
+	// requisições simultâneas não estornem o valor duas vezes
+	result := tx.Model(&bet).Where("status = ?", model.BetStatusPending).Update("status", model.BetStatusCancelled)
+	if result.Error != nil {
 		tx.Rollback()
-		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao cancelar aposta", "details": result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Apenas apostas pendentes podem ser canceladas"})
 		return
 	}
 
-	user.Balance += bet.Amount
-	if err := tx.Save(&user).Error; err != nil {
+	// Estorna o valor alterando só a coluna do saldo. O bônus da promoção não é
+	// devolvido, como na anulação
+	if err := adjustBalance(tx, userID, &bet.ID, model.LedgerTypeBetRefund, math.Max(bet.Amount-bet.BonusApplied, 0),
+		fmt.Sprintf("Cancelamento da aposta #%d", bet.ID)); err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar saldo do usuário", "details": err.Error()})
 		return
 	}
 
-	// Cancela a aposta
-	bet.Status = "cancelled"
-	if err := tx.Save(&bet).Error; err != nil {
+	if err := refreshRiskPositions(tx, bet.MatchID); err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao cancelar aposta", "details": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar exposição da partida", "details": err.Error()})
 		return
 	}
 
@@ -281,6 +476,7 @@ func CancelBet(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao finalizar transação", "details": err.Error()})
 		return
 	}
+	invalidateUserAnalytics(bet.UserID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Aposta cancelada com sucesso"})
 }
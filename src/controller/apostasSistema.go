@@ -0,0 +1,493 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/config"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxCustomSystemLegs é o maior número de palpites de uma aposta de sistema custom
+const maxCustomSystemLegs = 8
+
+// systemSizes retorna os tamanhos das combinações de cada tipo de aposta de
+// sistema: Trixie são 3 palpites em duplas e tripla (4 apostas), Yankee são 4
+// palpites em duplas, triplas e quádrupla (11 apostas) e Lucky 15 soma as
+// simples ao Yankee (15 apostas). No custom, size palpites dentre os informados.
+func systemSizes(betType string, size, legs int) ([]int, error) {
+	switch betType {
+	case model.SystemBetTypeTrixie:
+		if legs != 3 {
+			return nil, fmt.Errorf("a aposta trixie exige 3 palpites")
+		}
+		return []int{2, 3}, nil
+	case model.SystemBetTypeYankee:
+		if legs != 4 {
+			return nil, fmt.Errorf("a aposta yankee exige 4 palpites")
+		}
+		return []int{2, 3, 4}, nil
+	case model.SystemBetTypeLucky15:
+		if legs != 4 {
+			return nil, fmt.Errorf("a aposta lucky 15 exige 4 palpites")
+		}
+		return []int{1, 2, 3, 4}, nil
+	case model.SystemBetTypeCustom:
+		if legs > maxCustomSystemLegs {
+			return nil, fmt.Errorf("a aposta de sistema aceita no máximo %d palpites", maxCustomSystemLegs)
+		}
+		if size < 1 || size > legs {
+			return nil, fmt.Errorf("o tamanho das combinações deve estar entre 1 e %d", legs)
+		}
+		return []int{size}, nil
+	}
+	return nil, fmt.Errorf("tipo de aposta de sistema inválido")
+}
+
+// combinations gera, em ordem, todas as combinações de k índices dentre n
+func combinations(n, k int) [][]int {
+	var result [][]int
+	current := make([]int, 0, k)
+	var walk func(start int)
+	walk = func(start int) {
+		if len(current) == k {
+			result = append(result, append([]int(nil), current...))
+			return
+		}
+		for i := start; i <= n-(k-len(current)); i++ {
+			current = append(current, i)
+			walk(i + 1)
+			current = current[:len(current)-1]
+		}
+	}
+	walk(0)
+	return result
+}
+
+// combinationLegs converte a lista de posições da combinação ("1-2-4")
+func combinationLegs(legs string) []int {
+	var positions []int
+	for _, p := range strings.Split(legs, "-") {
+		if n, err := strconv.Atoi(p); err == nil {
+			positions = append(positions, n)
+		}
+	}
+	return positions
+}
+
+// legFactor é o quanto um palpite multiplica o valor da combinação. Palpites
+// devolvidos ou anulados valem 1 e os de meio resultado valem metade. O
+// segundo retorno é falso enquanto o palpite não foi decidido.
+func legFactor(leg model.SystemBetLeg) (float64, bool) {
+	switch leg.Status {
+	case model.BetStatusWon:
+		return leg.Odds, true
+	case model.BetStatusHalfWon:
+		return (leg.Odds + 1) / 2, true
+	case model.BetStatusPush, model.BetStatusVoid:
+		return 1, true
+	case model.BetStatusHalfLost:
+		return 0.5, true
+	case model.BetStatusLost:
+		return 0, true
+	}
+	return 0, false
+}
+
+// combinationOutcome calcula a situação e o prêmio de uma combinação a partir
+// dos palpites, indexados pela posição. A combinação é anulada quando todos os
+// palpites foram devolvidos ou anulados. O terceiro retorno é falso enquanto
+// algum palpite não foi decidido.
+func combinationOutcome(combo model.BetCombination, legs map[int]model.SystemBetLeg) (string, float64, bool) {
+	factor, void := 1.0, true
+	for _, position := range combinationLegs(combo.Legs) {
+		leg := legs[position]
+		f, ok := legFactor(leg)
+		if !ok {
+			return "", 0, false
+		}
+		factor *= f
+		if leg.Status != model.BetStatusVoid && leg.Status != model.BetStatusPush {
+			void = false
+		}
+	}
+
+	status := model.BetStatusWon
+	switch {
+	case void:
+		status = model.BetStatusVoid
+	case factor == 0:
+		status = model.BetStatusLost
+	}
+	return status, math.Round(combo.Stake*factor*100) / 100, true
+}
+
+// settleSystemBetLegs (re)liquida os palpites de apostas de sistema da partida
+// encerrada e as combinações afetadas. Com reason, as diferenças em
+// combinações já liquidadas são lançadas como correção.
+func settleSystemBetLegs(tx *gorm.DB, match model.Match, events []model.MatchEvent, stats *model.MatchStatistics, reason string) error {
+	var legs []model.SystemBetLeg
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("match_id = ? AND status <> ?", match.ID, model.BetStatusVoid).
+		Find(&legs).Error; err != nil {
+		return err
+	}
+
+	var changed []uint
+	seen := make(map[uint]bool)
+	for _, leg := range legs {
+		status, ok := betOutcome(model.Bet{BetType: leg.BetType, Selection: leg.Selection, Line: leg.Line}, match, events, stats)
+		if !ok || status == leg.Status {
+			continue
+		}
+		if err := tx.Model(&leg).Update("status", status).Error; err != nil {
+			return err
+		}
+		if !seen[leg.SystemBetID] {
+			seen[leg.SystemBetID] = true
+			changed = append(changed, leg.SystemBetID)
+		}
+	}
+
+	for _, id := range changed {
+		if err := settleCombinations(tx, id, reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// voidSystemBetLegs anula os palpites pendentes da partida; nas combinações
+// eles passam a valer 1
+func voidSystemBetLegs(tx *gorm.DB, match model.Match, reason string) error {
+	var ids []uint
+	if err := tx.Model(&model.SystemBetLeg{}).
+		Where("match_id = ? AND status = ?", match.ID, model.BetStatusPending).
+		Distinct().Pluck("system_bet_id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Model(&model.SystemBetLeg{}).
+		Where("match_id = ? AND status = ?", match.ID, model.BetStatusPending).
+		Update("status", model.BetStatusVoid).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := settleCombinations(tx, id, reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// settleCombinations liquida cada combinação cujos palpites já foram todos
+// decididos, creditando a diferença de prêmio no saldo do usuário. Quando
+// todas as combinações estão liquidadas, fecha a aposta de sistema.
+func settleCombinations(tx *gorm.DB, systemBetID uint, reason string) error {
+	var bet model.SystemBet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Legs").Preload("Combinations").
+		First(&bet, systemBetID).Error; err != nil {
+		return err
+	}
+
+	legs := make(map[int]model.SystemBetLeg, len(bet.Legs))
+	for _, leg := range bet.Legs {
+		legs[leg.Position] = leg
+	}
+
+	allSettled, allVoid := true, true
+	total := 0.0
+	for _, combo := range bet.Combinations {
+		status, payout, decided := combinationOutcome(combo, legs)
+		if !decided {
+			allSettled, allVoid = false, false
+			continue
+		}
+		if status != model.BetStatusVoid {
+			allVoid = false
+		}
+		total += payout
+		if status == combo.Status && payout == combo.Payout {
+			continue
+		}
+
+		if delta := payout - combo.Payout; delta != 0 {
+			entryType, description := model.LedgerTypeBetPayout,
+				fmt.Sprintf("Prêmio da combinação %s da aposta de sistema #%d", combo.Legs, bet.ID)
+			switch {
+			case combo.Status != model.BetStatusPending:
+				entryType, description = model.LedgerTypeCorrection,
+					fmt.Sprintf("Reliquidação da combinação %s da aposta de sistema #%d: %s", combo.Legs, bet.ID, reason)
+			case status == model.BetStatusVoid:
+				entryType, description = model.LedgerTypeBetRefund,
+					fmt.Sprintf("Estorno da combinação %s da aposta de sistema #%d", combo.Legs, bet.ID)
+			}
+			if err := adjustBalance(tx, bet.UserID, nil, entryType, delta, description); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&combo).Updates(map[string]interface{}{"status": status, "payout": payout}).Error; err != nil {
+			return err
+		}
+	}
+
+	if !allSettled {
+		return nil
+	}
+	status := model.BetStatusLost
+	switch {
+	case allVoid:
+		status = model.BetStatusVoid
+	case total > 0:
+		status = model.BetStatusWon
+	}
+	// A data de liquidação é a do fechamento original, mesmo após reliquidações
+	return tx.Model(&bet).Omit(clause.Associations).Updates(map[string]interface{}{
+		"status":     status,
+		"payout":     math.Round(total*100) / 100,
+		"settled_at": gorm.Expr("COALESCE(settled_at, ?)", time.Now()),
+	}).Error
+}
+
+// CreateSystemBet cria uma aposta de sistema, expandindo os palpites em todas
+// as combinações do tipo escolhido. O valor total é o valor unitário vezes o
+// número de combinações.
+func CreateSystemBet(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var input model.SystemBetCreate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	sizes, err := systemSizes(input.Type, input.Size, len(input.Selections))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user model.User
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+
+	// Cada palpite precisa ser de uma partida diferente e disponível para apostas
+	bet := model.SystemBet{
+		UserID:    userID,
+		Type:      input.Type,
+		UnitStake: input.UnitStake,
+		Status:    model.BetStatusPending,
+	}
+	if input.Type == model.SystemBetTypeCustom {
+		bet.Size = input.Size
+	}
+	matches := make(map[uint]bool, len(input.Selections))
+	legMatches := make([]model.Match, 0, len(input.Selections))
+	for i, s := range input.Selections {
+		if matches[s.MatchID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Os palpites da aposta de sistema devem ser de partidas diferentes"})
+			return
+		}
+		matches[s.MatchID] = true
+		if err := validateBetSelection(s.BetType, s.Selection, s.Line); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Palpite %d: %s", i+1, err.Error())})
+			return
+		}
+		match, ok := bettableMatch(c, s.MatchID)
+		if !ok {
+			return
+		}
+		legMatches = append(legMatches, match)
+
+		bet.Legs = append(bet.Legs, model.SystemBetLeg{
+			Position:  i + 1,
+			MatchID:   s.MatchID,
+			BetType:   s.BetType,
+			Selection: s.Selection,
+			Line:      s.Line,
+			Odds:      s.Odds,
+			Status:    model.BetStatusPending,
+		})
+	}
+
+	// A exposição de cada palpite é a soma dos valores das combinações que o contêm
+	legStakes := make([]float64, len(bet.Legs))
+	for _, size := range sizes {
+		for _, combo := range combinations(len(bet.Legs), size) {
+			positions := make([]string, len(combo))
+			odds := 1.0
+			for i, index := range combo {
+				positions[i] = strconv.Itoa(bet.Legs[index].Position)
+				odds *= bet.Legs[index].Odds
+				legStakes[index] += input.UnitStake
+			}
+			bet.Combinations = append(bet.Combinations, model.BetCombination{
+				Legs:   strings.Join(positions, "-"),
+				Size:   size,
+				Stake:  input.UnitStake,
+				Odds:   math.Round(odds*100) / 100,
+				Status: model.BetStatusPending,
+			})
+		}
+	}
+
+	// Limites e risco são verificados com a exposição real de cada palpite
+	legPayoutLimits := make([]float64, len(input.Selections))
+	for i, s := range input.Selections {
+		stake := math.Round(legStakes[i]*100) / 100
+		market, _ := betMarket(s.BetType, s.Selection)
+		_, legPayoutLimits[i], err = stakeLimits(config.DB, legMatches[i].TournamentID, market)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar limites de aposta", "details": err.Error()})
+			return
+		}
+		maxStake, limited, err := maxAllowedStake(config.DB, user, legMatches[i], market, s.Odds)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar limites de aposta", "details": err.Error()})
+			return
+		}
+		if limited && stake > maxStake {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":     fmt.Sprintf("Palpite %d: o valor máximo permitido é R$ %.2f e as combinações somam R$ %.2f", i+1, maxStake, stake),
+				"max_stake": maxStake,
+				"position":  i + 1,
+			})
+			return
+		}
+		message, details, err := checkBetRisk(config.DB, s.MatchID, s.BetType, s.Selection, s.Line, stake, s.Odds)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar risco da aposta", "details": err.Error()})
+			return
+		}
+		if message != "" {
+			response := gin.H{"error": fmt.Sprintf("Palpite %d: %s", i+1, message), "position": i + 1}
+			for k, v := range details {
+				response[k] = v
+			}
+			c.JSON(http.StatusConflict, response)
+			return
+		}
+	}
+
+	// O prêmio de cada combinação respeita o limite de prêmio mais restrito
+	// entre os seus palpites
+	for _, combo := range bet.Combinations {
+		maxPayout := math.Inf(1)
+		for _, position := range combinationLegs(combo.Legs) {
+			maxPayout = math.Min(maxPayout, legPayoutLimits[position-1])
+		}
+		if payout := combo.Stake * combo.Odds; payout > maxPayout {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      fmt.Sprintf("Combinação %s: o prêmio máximo permitido é R$ %.2f e a combinação pode pagar R$ %.2f", combo.Legs, maxPayout, payout),
+				"max_payout": maxPayout,
+				"legs":       combo.Legs,
+			})
+			return
+		}
+	}
+	bet.TotalStake = math.Round(input.UnitStake*float64(len(bet.Combinations))*100) / 100
+
+	if user.Balance < bet.TotalStake {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":       "Saldo insuficiente para realizar a aposta",
+			"total_stake": bet.TotalStake,
+		})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Os palpites e as combinações são gravados junto com a aposta
+		if err := tx.Create(&bet).Error; err != nil {
+			return err
+		}
+		if err := debitBalance(tx, userID, nil, model.LedgerTypeBetStake, bet.TotalStake,
+			fmt.Sprintf("Aposta de sistema #%d (%s, %d combinações)", bet.ID, bet.Type, len(bet.Combinations))); err != nil {
+			return err
+		}
+		// Os palpites passam a compor a exposição de cada partida
+		for _, leg := range bet.Legs {
+			if err := refreshRiskPositions(tx, leg.MatchID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errInsufficientBalance) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":       "Saldo insuficiente para realizar a aposta",
+				"total_stake": bet.TotalStake,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar aposta de sistema", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, bet)
+}
+
+// ListSystemBets lista as apostas de sistema do usuário, com filtro opcional de status
+func ListSystemBets(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := config.DB.Model(&model.SystemBet{}).Where("user_id = ?", c.GetUint("user_id"))
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	bets := []model.SystemBet{}
+	if err := query.Preload("Legs", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Combinations", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Offset(offset).Limit(limit).Order("created_at DESC").Find(&bets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar apostas de sistema", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": bets,
+		"meta": gin.H{
+			"total":  total,
+			"page":   page,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// GetSystemBet retorna uma aposta de sistema do usuário com os palpites e o
+// resultado de cada combinação
+func GetSystemBet(c *gin.Context) {
+	var bet model.SystemBet
+	if err := config.DB.Preload("Legs", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Combinations", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).
+		First(&bet).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Aposta de sistema não encontrada"})
+		return
+	}
+
+	c.JSON(http.StatusOK, bet)
+}
@@ -0,0 +1,247 @@
+package controller
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+)
+
+func TestCombinations(t *testing.T) {
+	tests := []struct {
+		n, k int
+		want [][]int
+	}{
+		{3, 2, [][]int{{0, 1}, {0, 2}, {1, 2}}},
+		{3, 3, [][]int{{0, 1, 2}}},
+		{4, 1, [][]int{{0}, {1}, {2}, {3}}},
+		{4, 2, [][]int{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}}},
+		{4, 3, [][]int{{0, 1, 2}, {0, 1, 3}, {0, 2, 3}, {1, 2, 3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.n)+"C"+strconv.Itoa(tt.k), func(t *testing.T) {
+			if got := combinations(tt.n, tt.k); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("combinations(%d, %d) = %v, esperado %v", tt.n, tt.k, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSystemSizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		betType string
+		size    int
+		legs    int
+		want    int
+		wantErr bool
+	}{
+		{"trixie", model.SystemBetTypeTrixie, 0, 3, 4, false},
+		{"yankee", model.SystemBetTypeYankee, 0, 4, 11, false},
+		{"lucky 15", model.SystemBetTypeLucky15, 0, 4, 15, false},
+		{"custom 3 de 5", model.SystemBetTypeCustom, 3, 5, 10, false},
+		{"trixie com 4 palpites", model.SystemBetTypeTrixie, 0, 4, 0, true},
+		{"yankee com 3 palpites", model.SystemBetTypeYankee, 0, 3, 0, true},
+		{"custom maior que os palpites", model.SystemBetTypeCustom, 4, 3, 0, true},
+		{"custom acima do limite", model.SystemBetTypeCustom, 2, maxCustomSystemLegs + 1, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sizes, err := systemSizes(tt.betType, tt.size, tt.legs)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("systemSizes aceitou %d palpites", tt.legs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("systemSizes: %v", err)
+			}
+			total := 0
+			for _, size := range sizes {
+				total += len(combinations(tt.legs, size))
+			}
+			if total != tt.want {
+				t.Errorf("%s gerou %d combinações, esperado %d", tt.name, total, tt.want)
+			}
+		})
+	}
+}
+
+func TestLegFactor(t *testing.T) {
+	tests := []struct {
+		status      string
+		want        float64
+		wantDecided bool
+	}{
+		{model.BetStatusWon, 2.5, true},
+		{model.BetStatusHalfWon, 1.75, true},
+		{model.BetStatusPush, 1, true},
+		{model.BetStatusVoid, 1, true},
+		{model.BetStatusHalfLost, 0.5, true},
+		{model.BetStatusLost, 0, true},
+		{model.BetStatusPending, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			got, decided := legFactor(model.SystemBetLeg{Odds: 2.5, Status: tt.status})
+			if got != tt.want || decided != tt.wantDecided {
+				t.Errorf("legFactor(%s) = %v, %v; esperado %v, %v", tt.status, got, decided, tt.want, tt.wantDecided)
+			}
+		})
+	}
+}
+
+// systemCombinations monta os palpites e as combinações de uma aposta de
+// sistema como na criação, com valor stake por combinação
+func systemCombinations(t *testing.T, betType string, odds []float64, statuses []string, stake float64) ([]model.BetCombination, map[int]model.SystemBetLeg) {
+	t.Helper()
+	sizes, err := systemSizes(betType, 0, len(odds))
+	if err != nil {
+		t.Fatalf("systemSizes: %v", err)
+	}
+
+	legs := make(map[int]model.SystemBetLeg, len(odds))
+	for i := range odds {
+		legs[i+1] = model.SystemBetLeg{Position: i + 1, Odds: odds[i], Status: statuses[i]}
+	}
+	var combos []model.BetCombination
+	for _, size := range sizes {
+		for _, combo := range combinations(len(odds), size) {
+			positions := make([]string, len(combo))
+			for i, index := range combo {
+				positions[i] = strconv.Itoa(index + 1)
+			}
+			combos = append(combos, model.BetCombination{Legs: strings.Join(positions, "-"), Size: size, Stake: stake})
+		}
+	}
+	return combos, legs
+}
+
+func TestCombinationOutcome(t *testing.T) {
+	won, lost, void, pending := model.BetStatusWon, model.BetStatusLost, model.BetStatusVoid, model.BetStatusPending
+
+	tests := []struct {
+		name     string
+		betType  string
+		odds     []float64
+		statuses []string
+		// want e wantStatus conferem algumas combinações, indexadas pelas posições
+		want        map[string]float64
+		wantStatus  map[string]string
+		wantTotal   float64
+		undecided   []string
+		combosCount int
+	}{
+		{
+			name:        "trixie toda vencida",
+			betType:     model.SystemBetTypeTrixie,
+			odds:        []float64{2, 3, 4},
+			statuses:    []string{won, won, won},
+			want:        map[string]float64{"1-2": 60, "1-3": 80, "2-3": 120, "1-2-3": 240},
+			wantTotal:   500,
+			combosCount: 4,
+		},
+		{
+			name:        "trixie com um palpite anulado vale 1 nas combinações",
+			betType:     model.SystemBetTypeTrixie,
+			odds:        []float64{2, 3, 4},
+			statuses:    []string{won, won, void},
+			want:        map[string]float64{"1-2": 60, "1-3": 20, "2-3": 30, "1-2-3": 60},
+			wantTotal:   170,
+			combosCount: 4,
+		},
+		{
+			name:        "trixie com dois palpites anulados devolve a dupla deles",
+			betType:     model.SystemBetTypeTrixie,
+			odds:        []float64{2, 3, 4},
+			statuses:    []string{won, void, void},
+			want:        map[string]float64{"1-2": 20, "1-3": 20, "2-3": 10, "1-2-3": 20},
+			wantStatus:  map[string]string{"1-2": won, "2-3": void, "1-2-3": won},
+			wantTotal:   70,
+			combosCount: 4,
+		},
+		{
+			name:        "yankee com um palpite perdido",
+			betType:     model.SystemBetTypeYankee,
+			odds:        []float64{2, 2, 2, 2},
+			statuses:    []string{lost, won, won, won},
+			want:        map[string]float64{"1-2": 0, "2-3": 40, "2-3-4": 80, "1-2-3-4": 0},
+			wantStatus:  map[string]string{"1-2": lost, "1-3-4": lost, "2-3-4": won},
+			wantTotal:   3*40 + 80,
+			combosCount: 11,
+		},
+		{
+			name:        "lucky 15 toda vencida",
+			betType:     model.SystemBetTypeLucky15,
+			odds:        []float64{2, 2, 2, 2},
+			statuses:    []string{won, won, won, won},
+			want:        map[string]float64{"1": 20, "1-2": 40, "1-2-3": 80, "1-2-3-4": 160},
+			wantTotal:   4*20 + 6*40 + 4*80 + 160,
+			combosCount: 15,
+		},
+		{
+			name:        "lucky 15 com um palpite anulado",
+			betType:     model.SystemBetTypeLucky15,
+			odds:        []float64{2, 2, 2, 2},
+			statuses:    []string{won, won, won, void},
+			want:        map[string]float64{"4": 10, "1-4": 20, "1-2-4": 40, "1-2-3": 80, "1-2-3-4": 80},
+			wantStatus:  map[string]string{"4": void, "1-4": won},
+			wantTotal:   70 + 180 + 200 + 80,
+			combosCount: 15,
+		},
+		{
+			name:        "yankee com palpite pendente só liquida as combinações sem ele",
+			betType:     model.SystemBetTypeYankee,
+			odds:        []float64{2, 2, 2, 2},
+			statuses:    []string{won, won, won, pending},
+			want:        map[string]float64{"1-2": 40, "1-2-3": 80},
+			undecided:   []string{"1-4", "2-3-4", "1-2-3-4"},
+			wantTotal:   3*40 + 80,
+			combosCount: 11,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			combos, legs := systemCombinations(t, tt.betType, tt.odds, tt.statuses, 10)
+			if len(combos) != tt.combosCount {
+				t.Fatalf("%d combinações, esperado %d", len(combos), tt.combosCount)
+			}
+
+			undecided := make(map[string]bool, len(tt.undecided))
+			for _, l := range tt.undecided {
+				undecided[l] = true
+			}
+			total := 0.0
+			for _, combo := range combos {
+				status, payout, decided := combinationOutcome(combo, legs)
+				if !decided {
+					if _, listed := tt.want[combo.Legs]; listed {
+						t.Errorf("combinação %s não foi decidida", combo.Legs)
+					}
+					delete(undecided, combo.Legs)
+					continue
+				}
+				total += payout
+				if want, ok := tt.want[combo.Legs]; ok && payout != want {
+					t.Errorf("combinação %s pagou %v, esperado %v", combo.Legs, payout, want)
+				}
+				if want, ok := tt.wantStatus[combo.Legs]; ok && status != want {
+					t.Errorf("combinação %s ficou %s, esperado %s", combo.Legs, status, want)
+				}
+			}
+			for l := range undecided {
+				t.Errorf("combinação %s foi decidida com palpite pendente", l)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("prêmio total %v, esperado %v", total, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -7,29 +7,56 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
 	"gorm.io/gorm"
 )
 
 type TournamentController struct {
-	DB *gorm.DB
+	DB    *gorm.DB
+	Cache util.Cache
 }
 
-func NewTournamentController(db *gorm.DB) *TournamentController {
-	return &TournamentController{DB: db}
+func NewTournamentController(db *gorm.DB, cache util.Cache) *TournamentController {
+	return &TournamentController{DB: db, Cache: cache}
 }
 
 func (c *TournamentController) CreateTournament(ctx *gin.Context) {
-	var tournament model.Tournament
-	if err := ctx.ShouldBindJSON(&tournament); err != nil {
+	var input model.TournamentCreate
+	if err := ctx.ShouldBindJSON(&input); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
 		return
 	}
 
-	if err := tournament.Validate(); err != nil {
+	if err := util.ValidateStruct(input); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	tournament := model.Tournament{
+		Name:                     input.Name,
+		Description:              input.Description,
+		StartDate:                input.StartDate,
+		EndDate:                  input.EndDate,
+		MaxTeams:                 input.MaxTeams,
+		RegistrationDeadline:     input.RegistrationDeadline,
+		TieBreakers:              input.TieBreakers,
+		YellowCardsForSuspension: input.YellowCardsForSuspension,
+		YellowSuspensionMatches:  input.YellowSuspensionMatches,
+		RedCardSuspensionMatches: input.RedCardSuspensionMatches,
+	}
+	// Pontuações informadas são gravadas depois da criação, pois o gorm troca
+	// o zero pelo valor padrão da coluna
+	points := map[string]interface{}{}
+	if input.PointsWin != nil {
+		points["points_win"] = *input.PointsWin
+	}
+	if input.PointsDraw != nil {
+		points["points_draw"] = *input.PointsDraw
+	}
+	if input.PointsLoss != nil {
+		points["points_loss"] = *input.PointsLoss
+	}
+
 	// Verificar se já existe um torneio com o mesmo nome
 	var existingTournament model.Tournament
 	if err := c.DB.Where("name = ?", tournament.Name).First(&existingTournament).Error; err == nil {
@@ -43,13 +70,43 @@ func (c *TournamentController) CreateTournament(ctx *gin.Context) {
 		return
 	}
 
+	// Validar critérios de desempate
+	if _, err := parseTieBreakers(tournament.TieBreakers); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// O prazo de inscrição termina até o início do torneio
+	if tournament.RegistrationDeadline != nil && tournament.RegistrationDeadline.After(tournament.StartDate) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "O prazo de inscrição deve terminar até a data de início do torneio"})
+		return
+	}
+
 	// Definir status inicial como pending
 	tournament.Status = "pending"
 
-	if err := c.DB.Create(&tournament).Error; err != nil {
+	err := c.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&tournament).Error; err != nil {
+			return err
+		}
+		if len(points) == 0 {
+			return nil
+		}
+		return tx.Model(&tournament).Updates(points).Error
+	})
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar torneio"})
 		return
 	}
+	if input.PointsWin != nil {
+		tournament.PointsWin = *input.PointsWin
+	}
+	if input.PointsDraw != nil {
+		tournament.PointsDraw = *input.PointsDraw
+	}
+	if input.PointsLoss != nil {
+		tournament.PointsLoss = *input.PointsLoss
+	}
 
 	ctx.JSON(http.StatusCreated, tournament)
 }
@@ -127,11 +184,15 @@ func (c *TournamentController) UpdateTournament(ctx *gin.Context) {
 		return
 	}
 
-	var updateData model.Tournament
+	var updateData model.TournamentUpdate
 	if err := ctx.ShouldBindJSON(&updateData); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
 		return
 	}
+	if err := util.ValidateStruct(updateData); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
 
 	// Verificar se já existe outro torneio com o mesmo nome
 	if updateData.Name != "" && updateData.Name != tournament.Name {
@@ -164,12 +225,61 @@ func (c *TournamentController) UpdateTournament(ctx *gin.Context) {
 	if updateData.Status != "" {
 		tournament.Status = updateData.Status
 	}
+	if updateData.PointsWin != nil {
+		tournament.PointsWin = *updateData.PointsWin
+	}
+	if updateData.PointsDraw != nil {
+		tournament.PointsDraw = *updateData.PointsDraw
+	}
+	if updateData.PointsLoss != nil {
+		tournament.PointsLoss = *updateData.PointsLoss
+	}
+	if updateData.TieBreakers != "" {
+		if _, err := parseTieBreakers(updateData.TieBreakers); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		tournament.TieBreakers = updateData.TieBreakers
+	}
+	if updateData.YellowCardsForSuspension != 0 {
+		tournament.YellowCardsForSuspension = updateData.YellowCardsForSuspension
+	}
+	if updateData.YellowSuspensionMatches != 0 {
+		tournament.YellowSuspensionMatches = updateData.YellowSuspensionMatches
+	}
+	if updateData.RedCardSuspensionMatches != 0 {
+		tournament.RedCardSuspensionMatches = updateData.RedCardSuspensionMatches
+	}
+	if updateData.MaxTeams != 0 {
+		// O limite não pode ficar abaixo dos times já inscritos
+		var registered int64
+		if err := c.DB.Model(&model.TournamentTeam{}).Where("tournament_id = ?", tournament.ID).
+			Count(&registered).Error; err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar times do torneio"})
+			return
+		}
+		if int64(updateData.MaxTeams) < registered {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "O limite de times não pode ser menor que a quantidade de times já inscritos"})
+			return
+		}
+		tournament.MaxTeams = updateData.MaxTeams
+	}
+	if updateData.RegistrationDeadline != nil {
+		tournament.RegistrationDeadline = updateData.RegistrationDeadline
+	}
+	if tournament.RegistrationDeadline != nil && tournament.RegistrationDeadline.After(tournament.StartDate) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "O prazo de inscrição deve terminar até a data de início do torneio"})
+		return
+	}
 
 	if err := c.DB.Save(&tournament).Error; err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar torneio"})
 		return
 	}
 
+	// As regras de pontuação podem ter mudado
+	invalidateStandings(c.Cache, tournament.ID)
+
 	ctx.JSON(http.StatusOK, tournament)
 }
 
@@ -205,3 +315,36 @@ func (c *TournamentController) DeleteTournament(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "Torneio excluído com sucesso"})
 }
+
+// GetStandings retorna a classificação do torneio calculada a partir das partidas encerradas
+func (c *TournamentController) GetStandings(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var tournament model.Tournament
+
+	if err := c.DB.First(&tournament, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Torneio não encontrado"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar torneio"})
+		return
+	}
+
+	var standings model.StandingsResponse
+	if err := c.Cache.Get(standingsCacheKey(tournament.ID), &standings); err == nil {
+		ctx.JSON(http.StatusOK, standings)
+		return
+	}
+
+	standings, err := computeStandings(c.DB, tournament)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular classificação", "details": err.Error()})
+		return
+	}
+
+	if err := c.Cache.Set(standingsCacheKey(tournament.ID), standings, util.StandingsCacheExpiry); err != nil {
+		util.LogError("Erro ao salvar classificação em cache", err)
+	}
+
+	ctx.JSON(http.StatusOK, standings)
+}
@@ -0,0 +1,757 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seedOrder retorna a ordem das cabeças de chave em um chaveamento de
+// tamanho size (potência de dois), de forma que os melhores só se
+// enfrentem nas fases finais. Ex.: 8 -> 1 8 4 5 2 7 3 6.
+func seedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		m := len(order) * 2
+		next := make([]int, 0, m)
+		for _, seed := range order {
+			next = append(next, seed, m+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// knockoutPairings monta os confrontos da primeira fase a partir dos times na
+// ordem de inscrição (cabeças de chave). Quando o número de times não é
+// potência de dois, os primeiros colocados folgam: o confronto fica com o
+// adversário 0. Retorna também o total de fases do chaveamento.
+func knockoutPairings(teamIDs []uint) ([]pairing, int) {
+	size, phases := 1, 0
+	for size < len(teamIDs) {
+		size *= 2
+		phases++
+	}
+
+	order := seedOrder(size)
+	seeded := func(seed int) uint {
+		if seed > len(teamIDs) {
+			return 0
+		}
+		return teamIDs[seed-1]
+	}
+
+	round := make([]pairing, 0, size/2)
+	for i := 0; i < size; i += 2 {
+		round = append(round, pairing{Home: seeded(order[i]), Away: seeded(order[i+1])})
+	}
+	return round, phases
+}
+
+// createBracket grava os confrontos de todas as fases do mata-mata. legDates
+// contém, para cada fase, as datas da ida e, se houver, da volta. Os
+// confrontos com folga são decididos na hora e o time avança direto.
+func createBracket(tx *gorm.DB, tournamentID uint, firstRound []pairing, legDates [][]time.Time, awayGoals bool, duration int) ([]model.BracketTie, error) {
+	phases := len(legDates)
+	byRound := make([][]model.BracketTie, phases+1)
+
+	// Cria da final para a primeira fase, para que cada confronto já saiba o seguinte
+	for round := phases; round >= 1; round-- {
+		count := 1 << (phases - round)
+		for pos := 0; pos < count; pos++ {
+			tie := model.BracketTie{
+				TournamentID:  tournamentID,
+				Round:         round,
+				Position:      pos,
+				Legs:          len(legDates[round-1]),
+				AwayGoalsRule: awayGoals,
+				FirstLegAt:    legDates[round-1][0],
+				MatchDuration: duration,
+				Status:        model.TieStatusPending,
+			}
+			if tie.Legs > 1 {
+				second := legDates[round-1][1]
+				tie.SecondLegAt = &second
+			}
+			if round < phases {
+				next := byRound[round+1][pos/2].ID
+				tie.NextTieID = &next
+			}
+			if err := tx.Create(&tie).Error; err != nil {
+				return nil, err
+			}
+			byRound[round] = append(byRound[round], tie)
+		}
+	}
+
+	var ties []model.BracketTie
+	for pos, p := range firstRound {
+		tie := byRound[1][pos]
+		if p.Home != 0 {
+			home := p.Home
+			tie.HomeTeamID = &home
+		}
+		if p.Away != 0 {
+			away := p.Away
+			tie.AwayTeamID = &away
+		}
+
+		switch {
+		case tie.HomeTeamID != nil && tie.AwayTeamID != nil:
+			if err := createTieMatches(tx, &tie); err != nil {
+				return nil, err
+			}
+		case tie.HomeTeamID != nil || tie.AwayTeamID != nil:
+			winner := p.Home
+			if winner == 0 {
+				winner = p.Away
+			}
+			if err := decideTie(tx, &tie, winner, model.TieDecidedByBye); err != nil {
+				return nil, err
+			}
+		}
+		if err := tx.Save(&tie).Error; err != nil {
+			return nil, err
+		}
+		ties = append(ties, tie)
+	}
+	return ties, nil
+}
+
+// tieScheduleDays é quantos dias uma partida de mata-mata pode ser adiada em
+// relação à data prevista quando um dos times já joga no horário
+const tieScheduleDays = 14
+
+// createTieMatches cria as partidas de um confronto cujos times já são conhecidos.
+// Na volta, o mando é invertido. Se um dos times já tiver partida na data
+// prevista, a partida vai para o primeiro dia seguinte livre, e a volta é
+// adiada junto com a ida.
+func createTieMatches(tx *gorm.DB, tie *model.BracketTie) error {
+	var teams []model.Team
+	if err := tx.Where("id IN ?", []uint{*tie.HomeTeamID, *tie.AwayTeamID}).Find(&teams).Error; err != nil {
+		return err
+	}
+	stadiums := make(map[uint]string, len(teams))
+	for _, team := range teams {
+		stadiums[team.ID] = team.Stadium
+	}
+
+	duration := time.Duration(tie.MatchDuration) * time.Minute
+	legs := []struct {
+		home, away uint
+		start      time.Time
+	}{{*tie.HomeTeamID, *tie.AwayTeamID, tie.FirstLegAt}}
+	if tie.Legs > 1 && tie.SecondLegAt != nil {
+		legs = append(legs, struct {
+			home, away uint
+			start      time.Time
+		}{*tie.AwayTeamID, *tie.HomeTeamID, *tie.SecondLegAt})
+	}
+
+	teamIDs := []uint{*tie.HomeTeamID, *tie.AwayTeamID}
+	delay := 0
+	for i, leg := range legs {
+		for ; ; delay++ {
+			if delay > tieScheduleDays {
+				return fmt.Errorf("não foi possível agendar o jogo %d do confronto %d sem conflito de horário com outras partidas dos times", i+1, tie.ID)
+			}
+			busy, err := teamsBusy(tx, teamIDs, leg.start.AddDate(0, 0, delay), leg.start.AddDate(0, 0, delay).Add(duration))
+			if err != nil {
+				return err
+			}
+			if !busy {
+				break
+			}
+		}
+		leg.start = leg.start.AddDate(0, 0, delay)
+
+		match := model.Match{
+			TournamentID: tie.TournamentID,
+			Round:        tie.Round,
+			BracketTieID: &tie.ID,
+			Leg:          i + 1,
+			HomeTeamID:   leg.home,
+			AwayTeamID:   leg.away,
+			StartTime:    leg.start,
+			EndTime:      leg.start.Add(duration),
+			Status:       model.MatchStatusScheduled,
+			MarketStatus: model.MarketStatusOpen,
+			Stadium:      stadiums[leg.home],
+			Referee:      defaultReferee,
+		}
+		if err := tx.Create(&match).Error; err != nil {
+			return err
+		}
+	}
+
+	tie.Status = model.TieStatusReady
+	return nil
+}
+
+// decideTie registra o vencedor do confronto e o coloca na próxima fase.
+// Quando os dois times do próximo confronto são conhecidos, suas partidas
+// são criadas.
+func decideTie(tx *gorm.DB, tie *model.BracketTie, winner uint, decidedBy string) error {
+	tie.WinnerTeamID = &winner
+	tie.DecidedBy = decidedBy
+	tie.Status = model.TieStatusDecided
+	if tie.NextTieID == nil {
+		return nil
+	}
+
+	var next model.BracketTie
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&next, *tie.NextTieID).Error; err != nil {
+		return err
+	}
+	if tie.Position%2 == 0 {
+		next.HomeTeamID = &winner
+	} else {
+		next.AwayTeamID = &winner
+	}
+	if next.HomeTeamID != nil && next.AwayTeamID != nil && next.Status == model.TieStatusPending {
+		if err := createTieMatches(tx, &next); err != nil {
+			return err
+		}
+	}
+	return tx.Save(&next).Error
+}
+
+// tieWinner aplica as regras do confronto às partidas disputadas: placar
+// (ou placar agregado), gols fora de casa, prorrogação e pênaltis, nesta
+// ordem. Retorna 0 quando o confronto continua empatado com os dados
+// disponíveis.
+func tieWinner(tie model.BracketTie, legs []model.Match) (uint, string) {
+	if tie.HomeTeamID == nil || tie.AwayTeamID == nil || len(legs) == 0 {
+		return 0, ""
+	}
+	teamA, teamB := *tie.HomeTeamID, *tie.AwayTeamID
+	pick := func(values map[uint]int) uint {
+		switch {
+		case values[teamA] > values[teamB]:
+			return teamA
+		case values[teamA] < values[teamB]:
+			return teamB
+		}
+		return 0
+	}
+	twoLegs := len(legs) > 1
+
+	goals := make(map[uint]int)
+	away := make(map[uint]int)
+	for _, m := range legs {
+		goals[m.HomeTeamID] += m.HomeScore
+		goals[m.AwayTeamID] += m.AwayScore
+		away[m.AwayTeamID] += m.AwayScore
+	}
+
+	regular := model.TieDecidedByScore
+	if twoLegs {
+		regular = model.TieDecidedByAggregate
+	}
+	if w := pick(goals); w != 0 {
+		return w, regular
+	}
+	if tie.AwayGoalsRule && twoLegs {
+		if w := pick(away); w != 0 {
+			return w, model.TieDecidedByAwayGoals
+		}
+	}
+
+	last := legs[len(legs)-1]
+	if last.HomeExtraTimeScore != nil && last.AwayExtraTimeScore != nil {
+		goals[last.HomeTeamID] += *last.HomeExtraTimeScore
+		goals[last.AwayTeamID] += *last.AwayExtraTimeScore
+		away[last.AwayTeamID] += *last.AwayExtraTimeScore
+		if w := pick(goals); w != 0 {
+			return w, model.TieDecidedByExtraTime
+		}
+		if tie.AwayGoalsRule && twoLegs {
+			if w := pick(away); w != 0 {
+				return w, model.TieDecidedByAwayGoals
+			}
+		}
+	}
+
+	if last.HomePenalties != nil && last.AwayPenalties != nil {
+		penalties := map[uint]int{
+			last.HomeTeamID: *last.HomePenalties,
+			last.AwayTeamID: *last.AwayPenalties,
+		}
+		if w := pick(penalties); w != 0 {
+			return w, model.TieDecidedByPenalties
+		}
+	}
+
+	return 0, ""
+}
+
+// tieLegs retorna as partidas encerradas do confronto, em ordem de ida e
+// volta, substituindo a partida informada pela versão ainda não gravada.
+func tieLegs(db *gorm.DB, tieID uint, current model.Match) ([]model.Match, error) {
+	var legs []model.Match
+	if err := db.Where("bracket_tie_id = ? AND status = ? AND id <> ?", tieID, model.MatchStatusFinished, current.ID).
+		Find(&legs).Error; err != nil {
+		return nil, err
+	}
+	legs = append(legs, current)
+	sort.Slice(legs, func(i, j int) bool { return legs[i].Leg < legs[j].Leg })
+	return legs, nil
+}
+
+// validateKnockoutFinish confere a prorrogação e os pênaltis informados ao
+// encerrar uma partida. Só podem ser informados na partida que decide o
+// confronto e apenas enquanto ele continuar empatado.
+func validateKnockoutFinish(db *gorm.DB, match model.Match) error {
+	hasExtraTime := match.HomeExtraTimeScore != nil || match.AwayExtraTimeScore != nil
+	hasPenalties := match.HomePenalties != nil || match.AwayPenalties != nil
+	if hasExtraTime && (match.HomeExtraTimeScore == nil || match.AwayExtraTimeScore == nil) {
+		return errors.New("informe os gols da prorrogação dos dois times")
+	}
+	if hasPenalties && (match.HomePenalties == nil || match.AwayPenalties == nil) {
+		return errors.New("informe as cobranças de pênalti convertidas pelos dois times")
+	}
+
+	if match.BracketTieID == nil {
+		if hasExtraTime || hasPenalties {
+			return errors.New("prorrogação e pênaltis só existem em partidas de mata-mata")
+		}
+		return nil
+	}
+
+	var tie model.BracketTie
+	if err := db.First(&tie, *match.BracketTieID).Error; err != nil {
+		return err
+	}
+	if match.Leg < tie.Legs {
+		if hasExtraTime || hasPenalties {
+			return errors.New("prorrogação e pênaltis só podem ser informados na partida de volta")
+		}
+		return nil
+	}
+
+	legs, err := tieLegs(db, tie.ID, match)
+	if err != nil {
+		return err
+	}
+	return validateTieDecision(tie, legs)
+}
+
+// validateTieDecision confere a prorrogação e os pênaltis da última partida
+// do confronto, que já vem em legs com os dados informados no encerramento
+func validateTieDecision(tie model.BracketTie, legs []model.Match) error {
+	match := legs[len(legs)-1]
+	hasExtraTime := match.HomeExtraTimeScore != nil || match.AwayExtraTimeScore != nil
+	hasPenalties := match.HomePenalties != nil || match.AwayPenalties != nil
+	if len(legs) < tie.Legs {
+		return errors.New("a partida de ida do confronto ainda não foi encerrada")
+	}
+
+	regular := legs[len(legs)-1]
+	regular.HomeExtraTimeScore, regular.AwayExtraTimeScore = nil, nil
+	regular.HomePenalties, regular.AwayPenalties = nil, nil
+	legs[len(legs)-1] = regular
+	if w, _ := tieWinner(tie, legs); w != 0 {
+		if hasExtraTime || hasPenalties {
+			return errors.New("o confronto foi decidido no tempo regulamentar; não informe prorrogação nem pênaltis")
+		}
+		return nil
+	}
+	if !hasExtraTime && !hasPenalties {
+		return errors.New("o confronto terminou empatado; informe a prorrogação ou os pênaltis")
+	}
+
+	if hasExtraTime {
+		withExtraTime := regular
+		withExtraTime.HomeExtraTimeScore = match.HomeExtraTimeScore
+		withExtraTime.AwayExtraTimeScore = match.AwayExtraTimeScore
+		legs[len(legs)-1] = withExtraTime
+		w, _ := tieWinner(tie, legs)
+		if w != 0 && hasPenalties {
+			return errors.New("o confronto foi decidido na prorrogação; não informe pênaltis")
+		}
+		if w == 0 && !hasPenalties {
+			return errors.New("o confronto continua empatado após a prorrogação; informe os pênaltis")
+		}
+	}
+
+	if hasPenalties {
+		legs[len(legs)-1] = match
+		if w, _ := tieWinner(tie, legs); w == 0 {
+			return errors.New("a disputa de pênaltis não pode terminar empatada")
+		}
+	}
+	return nil
+}
+
+// advanceBracket atualiza o confronto de uma partida de mata-mata encerrada.
+// Quando todas as partidas do confronto terminaram, o vencedor avança.
+func advanceBracket(tx *gorm.DB, match model.Match) (gin.H, error) {
+	if match.BracketTieID == nil {
+		return nil, nil
+	}
+
+	var tie model.BracketTie
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tie, *match.BracketTieID).Error; err != nil {
+		return nil, err
+	}
+	if tie.Status == model.TieStatusDecided {
+		return nil, nil
+	}
+
+	legs, err := tieLegs(tx, tie.ID, match)
+	if err != nil {
+		return nil, err
+	}
+	if len(legs) < tie.Legs {
+		return gin.H{"bracket_tie_id": tie.ID, "tie_status": tie.Status}, nil
+	}
+
+	winner, decidedBy := tieWinner(tie, legs)
+	if winner == 0 {
+		return nil, fmt.Errorf("confronto %d sem vencedor", tie.ID)
+	}
+	if err := decideTie(tx, &tie, winner, decidedBy); err != nil {
+		return nil, err
+	}
+	if err := tx.Save(&tie).Error; err != nil {
+		return nil, err
+	}
+
+	return gin.H{
+		"bracket_tie_id": tie.ID,
+		"tie_status":     tie.Status,
+		"winner_team_id": winner,
+		"decided_by":     decidedBy,
+		"next_tie_id":    tie.NextTieID,
+	}, nil
+}
+
+// bracketRoundName retorna o nome da fase conforme a distância até a final
+func bracketRoundName(round, phases int) string {
+	switch phases - round {
+	case 0:
+		return "Final"
+	case 1:
+		return "Semifinal"
+	case 2:
+		return "Quartas de final"
+	case 3:
+		return "Oitavas de final"
+	}
+	return fmt.Sprintf("Fase %d", round)
+}
+
+// buildBracket monta a árvore do mata-mata de um torneio
+func buildBracket(db *gorm.DB, tournamentID uint) (model.BracketResponse, error) {
+	response := model.BracketResponse{TournamentID: tournamentID, Rounds: []model.BracketRound{}}
+
+	var ties []model.BracketTie
+	if err := db.Where("tournament_id = ?", tournamentID).Order("round, position").Find(&ties).Error; err != nil {
+		return response, err
+	}
+	if len(ties) == 0 {
+		return response, nil
+	}
+
+	var matches []model.Match
+	if err := db.Where("bracket_tie_id IS NOT NULL AND tournament_id = ?", tournamentID).
+		Order("leg").Find(&matches).Error; err != nil {
+		return response, err
+	}
+	byTie := make(map[uint][]model.Match)
+	for _, m := range matches {
+		byTie[*m.BracketTieID] = append(byTie[*m.BracketTieID], m)
+	}
+
+	phases := ties[len(ties)-1].Round
+	for _, tie := range ties {
+		item := model.BracketTieResponse{
+			ID:             tie.ID,
+			Round:          tie.Round,
+			Position:       tie.Position,
+			HomeTeamID:     tie.HomeTeamID,
+			AwayTeamID:     tie.AwayTeamID,
+			Legs:           tie.Legs,
+			AwayGoalsRule:  tie.AwayGoalsRule,
+			WinnerTeamID:   tie.WinnerTeamID,
+			DecidedBy:      tie.DecidedBy,
+			DecisionReason: tie.DecisionReason,
+			Status:         tie.Status,
+			NextTieID:      tie.NextTieID,
+			Matches:        []model.MatchResponse{},
+		}
+		for _, m := range byTie[tie.ID] {
+			item.Matches = append(item.Matches, toMatchResponse(m))
+			if m.Status != model.MatchStatusFinished && m.Status != model.MatchStatusLive && m.Status != model.MatchStatusHalfTime {
+				continue
+			}
+			home, away := m.HomeScore, m.AwayScore
+			if m.HomeExtraTimeScore != nil && m.AwayExtraTimeScore != nil {
+				home += *m.HomeExtraTimeScore
+				away += *m.AwayExtraTimeScore
+			}
+			if tie.HomeTeamID != nil && m.HomeTeamID == *tie.HomeTeamID {
+				item.HomeAggregate += home
+				item.AwayAggregate += away
+			} else {
+				item.HomeAggregate += away
+				item.AwayAggregate += home
+			}
+		}
+
+		if len(response.Rounds) < tie.Round {
+			response.Rounds = append(response.Rounds, model.BracketRound{
+				Round: tie.Round,
+				Name:  bracketRoundName(tie.Round, phases),
+			})
+		}
+		round := &response.Rounds[tie.Round-1]
+		round.Ties = append(round.Ties, item)
+
+		if tie.NextTieID == nil && tie.Status == model.TieStatusDecided {
+			response.ChampionID = tie.WinnerTeamID
+		}
+	}
+
+	return response, nil
+}
+
+// GetBracket retorna o chaveamento do mata-mata de um torneio
+func (c *TournamentController) GetBracket(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var tournament model.Tournament
+	if err := c.DB.First(&tournament, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Torneio não encontrado"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar torneio"})
+		return
+	}
+
+	bracket, err := buildBracket(c.DB, tournament.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao montar chaveamento", "details": err.Error()})
+		return
+	}
+	if len(bracket.Rounds) == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Este torneio não possui chaveamento de mata-mata"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, bracket)
+}
+
+// errTieClosed indica que o confronto foi decidido por outra requisição
+var errTieClosed = errors.New("este confronto já foi decidido")
+
+// tieMatchesByLeg retorna, para cada jogo do confronto, a partida mais recente.
+// Uma partida remarcada substitui a que foi cancelada ou abandonada.
+func tieMatchesByLeg(db *gorm.DB, tieID uint) (map[int]model.Match, error) {
+	var matches []model.Match
+	if err := db.Where("bracket_tie_id = ?", tieID).Order("leg, id").Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	byLeg := make(map[int]model.Match, len(matches))
+	for _, m := range matches {
+		byLeg[m.Leg] = m
+	}
+	return byLeg, nil
+}
+
+// withoutResult informa se a partida terminou sem resultado
+func withoutResult(match model.Match) bool {
+	return match.Status == model.MatchStatusCancelled || match.Status == model.MatchStatusAbandoned
+}
+
+// loadUndecidedTie busca um confronto do torneio que ainda não foi decidido e
+// cujos times já são conhecidos
+func (c *TournamentController) loadUndecidedTie(ctx *gin.Context) (*model.BracketTie, bool) {
+	var tie model.BracketTie
+	if err := c.DB.Where("id = ? AND tournament_id = ?", ctx.Param("tie_id"), ctx.Param("id")).First(&tie).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Confronto não encontrado"})
+			return nil, false
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar confronto"})
+		return nil, false
+	}
+	if tie.Status == model.TieStatusDecided {
+		ctx.JSON(http.StatusConflict, gin.H{"error": errTieClosed.Error()})
+		return nil, false
+	}
+	if tie.HomeTeamID == nil || tie.AwayTeamID == nil {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Os times do confronto ainda não foram definidos"})
+		return nil, false
+	}
+	return &tie, true
+}
+
+// ReplayTieMatch remarca a partida cancelada ou abandonada de um confronto,
+// com os mesmos times e mando. O confronto segue quando a nova partida terminar.
+func (c *TournamentController) ReplayTieMatch(ctx *gin.Context) {
+	tie, ok := c.loadUndecidedTie(ctx)
+	if !ok {
+		return
+	}
+
+	var input model.TieReplay
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	byLeg, err := tieMatchesByLeg(c.DB, tie.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar partidas do confronto"})
+		return
+	}
+	var voided *model.Match
+	for leg := 1; leg <= tie.Legs; leg++ {
+		if m, found := byLeg[leg]; found && withoutResult(m) {
+			voided = &m
+			break
+		}
+	}
+	if voided == nil {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "O confronto não tem partida cancelada ou abandonada para remarcar"})
+		return
+	}
+	// A ida precisa terminar antes da volta, que decide o confronto
+	for leg := voided.Leg + 1; leg <= tie.Legs; leg++ {
+		if m, found := byLeg[leg]; found && !withoutResult(m) && !input.StartTime.Before(m.StartTime) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "A partida de ida precisa ser remarcada para antes da partida de volta"})
+			return
+		}
+	}
+
+	duration := voided.EndTime.Sub(voided.StartTime)
+	if tie.MatchDuration > 0 {
+		duration = time.Duration(tie.MatchDuration) * time.Minute
+	}
+	replay := model.Match{
+		TournamentID: voided.TournamentID,
+		Round:        voided.Round,
+		BracketTieID: &tie.ID,
+		Leg:          voided.Leg,
+		HomeTeamID:   voided.HomeTeamID,
+		AwayTeamID:   voided.AwayTeamID,
+		StartTime:    input.StartTime,
+		EndTime:      input.StartTime.Add(duration),
+		Status:       model.MatchStatusScheduled,
+		MarketStatus: model.MarketStatusOpen,
+		Stadium:      voided.Stadium,
+		Referee:      voided.Referee,
+	}
+
+	busy, err := teamsBusy(c.DB, []uint{replay.HomeTeamID, replay.AwayTeamID}, replay.StartTime, replay.EndTime)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar conflito de horário"})
+		return
+	}
+	if busy {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Já existe uma partida agendada para este time no mesmo horário"})
+		return
+	}
+
+	err = c.DB.Transaction(func(tx *gorm.DB) error {
+		var locked model.BracketTie
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, tie.ID).Error; err != nil {
+			return err
+		}
+		if locked.Status == model.TieStatusDecided {
+			return errTieClosed
+		}
+		return tx.Create(&replay).Error
+	})
+	if err != nil {
+		if errors.Is(err, errTieClosed) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remarcar partida", "details": err.Error()})
+		return
+	}
+
+	util.LogInfo(fmt.Sprintf("Confronto %d: jogo %d remarcado na partida %d (substitui a partida %d)", tie.ID, replay.Leg, replay.ID, voided.ID))
+	ctx.JSON(http.StatusCreated, toMatchResponse(replay))
+}
+
+// DecideTie define o vencedor de um confronto com partida cancelada ou
+// abandonada, sem remarcá-la. O vencedor avança para a próxima fase.
+func (c *TournamentController) DecideTie(ctx *gin.Context) {
+	tie, ok := c.loadUndecidedTie(ctx)
+	if !ok {
+		return
+	}
+
+	var input model.TieDecision
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if input.WinnerTeamID != *tie.HomeTeamID && input.WinnerTeamID != *tie.AwayTeamID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "O vencedor precisa ser um dos times do confronto"})
+		return
+	}
+
+	byLeg, err := tieMatchesByLeg(c.DB, tie.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar partidas do confronto"})
+		return
+	}
+	hasVoided := false
+	for _, m := range byLeg {
+		if withoutResult(m) {
+			hasVoided = true
+		} else if m.Status != model.MatchStatusFinished {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Cancele ou encerre as demais partidas do confronto antes de decidi-lo"})
+			return
+		}
+	}
+	if !hasVoided {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Só confrontos com partida cancelada ou abandonada podem ser decididos pela organização"})
+		return
+	}
+
+	err = c.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(tie, tie.ID).Error; err != nil {
+			return err
+		}
+		if tie.Status == model.TieStatusDecided {
+			return errTieClosed
+		}
+		if err := decideTie(tx, tie, input.WinnerTeamID, model.TieDecidedByAdmin); err != nil {
+			return err
+		}
+		tie.DecisionReason = input.Reason
+		return tx.Save(tie).Error
+	})
+	if err != nil {
+		if errors.Is(err, errTieClosed) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao decidir confronto", "details": err.Error()})
+		return
+	}
+
+	util.LogInfo(fmt.Sprintf("Confronto %d decidido pela organização: vencedor %d", tie.ID, input.WinnerTeamID))
+	ctx.JSON(http.StatusOK, tie)
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+)
+
+// tieLeg monta uma partida de confronto; extra e penalties, quando
+// informados, são o placar da prorrogação e dos pênaltis (mandante, visitante)
+func tieLeg(leg int, home, away uint, homeScore, awayScore int, extra, penalties []int) model.Match {
+	m := model.Match{Leg: leg, HomeTeamID: home, AwayTeamID: away, HomeScore: homeScore, AwayScore: awayScore}
+	if extra != nil {
+		m.HomeExtraTimeScore, m.AwayExtraTimeScore = &extra[0], &extra[1]
+	}
+	if penalties != nil {
+		m.HomePenalties, m.AwayPenalties = &penalties[0], &penalties[1]
+	}
+	return m
+}
+
+// knockoutTie monta um confronto entre os times 1 (mandante da ida) e 2
+func knockoutTie(legs int, awayGoals bool) model.BracketTie {
+	home, away := uint(1), uint(2)
+	return model.BracketTie{HomeTeamID: &home, AwayTeamID: &away, Legs: legs, AwayGoalsRule: awayGoals}
+}
+
+func TestTieWinner(t *testing.T) {
+	tests := []struct {
+		name      string
+		tie       model.BracketTie
+		legs      []model.Match
+		want      uint
+		decidedBy string
+	}{
+		{"jogo único no tempo regulamentar", knockoutTie(1, false),
+			[]model.Match{tieLeg(1, 1, 2, 2, 1, nil, nil)}, 1, model.TieDecidedByScore},
+		{"jogo único empatado sem prorrogação", knockoutTie(1, false),
+			[]model.Match{tieLeg(1, 1, 2, 1, 1, nil, nil)}, 0, ""},
+		{"placar agregado", knockoutTie(2, false),
+			[]model.Match{tieLeg(1, 1, 2, 0, 1, nil, nil), tieLeg(2, 2, 1, 1, 3, nil, nil)}, 1, model.TieDecidedByAggregate},
+		{"gols fora de casa", knockoutTie(2, true),
+			[]model.Match{tieLeg(1, 1, 2, 2, 1, nil, nil), tieLeg(2, 2, 1, 1, 0, nil, nil)}, 2, model.TieDecidedByAwayGoals},
+		{"agregado empatado sem a regra dos gols fora", knockoutTie(2, false),
+			[]model.Match{tieLeg(1, 1, 2, 2, 1, nil, nil), tieLeg(2, 2, 1, 1, 0, nil, nil)}, 0, ""},
+		{"prorrogação", knockoutTie(2, false),
+			[]model.Match{tieLeg(1, 1, 2, 1, 1, nil, nil), tieLeg(2, 2, 1, 0, 0, []int{1, 0}, nil)}, 2, model.TieDecidedByExtraTime},
+		{"gol fora de casa na prorrogação", knockoutTie(2, true),
+			[]model.Match{tieLeg(1, 1, 2, 1, 1, nil, nil), tieLeg(2, 2, 1, 1, 1, []int{1, 1}, nil)}, 1, model.TieDecidedByAwayGoals},
+		{"pênaltis", knockoutTie(1, false),
+			[]model.Match{tieLeg(1, 1, 2, 1, 1, []int{0, 0}, []int{4, 5})}, 2, model.TieDecidedByPenalties},
+		{"pênaltis empatados não decidem", knockoutTie(1, false),
+			[]model.Match{tieLeg(1, 1, 2, 1, 1, []int{0, 0}, []int{3, 3})}, 0, ""},
+		{"confronto sem os dois times", model.BracketTie{Legs: 1},
+			[]model.Match{tieLeg(1, 1, 2, 2, 0, nil, nil)}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, decidedBy := tieWinner(tt.tie, tt.legs)
+			if got != tt.want || decidedBy != tt.decidedBy {
+				t.Errorf("tieWinner = %d, %q; esperado %d, %q", got, decidedBy, tt.want, tt.decidedBy)
+			}
+		})
+	}
+}
+
+func TestValidateTieDecision(t *testing.T) {
+	tests := []struct {
+		name    string
+		tie     model.BracketTie
+		legs    []model.Match
+		wantErr bool
+	}{
+		{"decidido no tempo regulamentar", knockoutTie(1, false),
+			[]model.Match{tieLeg(1, 1, 2, 2, 1, nil, nil)}, false},
+		{"prorrogação com confronto já decidido", knockoutTie(1, false),
+			[]model.Match{tieLeg(1, 1, 2, 2, 1, []int{0, 0}, nil)}, true},
+		{"empate sem prorrogação nem pênaltis", knockoutTie(1, false),
+			[]model.Match{tieLeg(1, 1, 2, 1, 1, nil, nil)}, true},
+		{"decidido na prorrogação", knockoutTie(1, false),
+			[]model.Match{tieLeg(1, 1, 2, 1, 1, []int{1, 0}, nil)}, false},
+		{"pênaltis após prorrogação decisiva", knockoutTie(1, false),
+			[]model.Match{tieLeg(1, 1, 2, 1, 1, []int{1, 0}, []int{4, 3})}, true},
+		{"prorrogação empatada sem pênaltis", knockoutTie(1, false),
+			[]model.Match{tieLeg(1, 1, 2, 1, 1, []int{1, 1}, nil)}, true},
+		{"decidido nos pênaltis", knockoutTie(1, false),
+			[]model.Match{tieLeg(1, 1, 2, 1, 1, []int{1, 1}, []int{5, 4})}, false},
+		{"pênaltis empatados", knockoutTie(1, false),
+			[]model.Match{tieLeg(1, 1, 2, 1, 1, []int{0, 0}, []int{4, 4})}, true},
+		{"gols fora decidem antes da prorrogação", knockoutTie(2, true),
+			[]model.Match{tieLeg(1, 1, 2, 2, 1, nil, nil), tieLeg(2, 2, 1, 1, 0, []int{0, 0}, nil)}, true},
+		{"volta sem a ida encerrada", knockoutTie(2, false),
+			[]model.Match{tieLeg(2, 2, 1, 1, 0, nil, nil)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTieDecision(tt.tie, tt.legs)
+			if tt.wantErr && err == nil {
+				t.Errorf("validateTieDecision aceitou o encerramento")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("validateTieDecision: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/config"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errStaleTransition indica que o status da partida mudou depois da verificação inicial
+var errStaleTransition = errors.New("o status da partida foi alterado por outra requisição")
+
+// matchTransitions define, para cada status, os status seguintes permitidos.
+// finished, cancelled e abandoned são estados finais.
+var matchTransitions = map[string][]string{
+	model.MatchStatusScheduled: {model.MatchStatusLive, model.MatchStatusPostponed, model.MatchStatusCancelled},
+	model.MatchStatusLive:      {model.MatchStatusHalfTime, model.MatchStatusFinished, model.MatchStatusAbandoned},
+	model.MatchStatusHalfTime:  {model.MatchStatusLive, model.MatchStatusAbandoned},
+	model.MatchStatusPostponed: {model.MatchStatusScheduled, model.MatchStatusCancelled},
+}
+
+// kickoffTolerance é a antecedência máxima com que uma partida pode ser iniciada
+const kickoffTolerance = time.Hour
+
+// canTransition informa se a partida pode passar de um status para outro
+func canTransition(from, to string) bool {
+	for _, next := range matchTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+type MatchLifecycleController struct {
+	DB    *gorm.DB
+	Cache util.Cache
+	// PostponementWindow é o atraso máximo de uma partida adiada antes de suas apostas serem anuladas
+	PostponementWindow time.Duration
+}
+
+func NewMatchLifecycleController(db *gorm.DB, cache util.Cache) *MatchLifecycleController {
+	return &MatchLifecycleController{
+		DB:                 db,
+		Cache:              cache,
+		PostponementWindow: config.LoadConfig().Betting.PostponementVoidWindow,
+	}
+}
+
+// sideEffect é executado na mesma transação da mudança de status
+type sideEffect func(tx *gorm.DB, match *model.Match) (gin.H, error)
+
+// loadMatchFor busca a partida e verifica se a transição é permitida. Quando
+// from é informado, a partida também precisa estar em um desses status.
+func (c *MatchLifecycleController) loadMatchFor(ctx *gin.Context, target string, from ...string) (*model.Match, bool) {
+	var match model.Match
+	if err := c.DB.First(&match, ctx.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Partida não encontrada"})
+			return nil, false
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar partida"})
+		return nil, false
+	}
+
+	allowed := canTransition(match.Status, target)
+	if allowed && len(from) > 0 {
+		allowed = false
+		for _, status := range from {
+			if match.Status == status {
+				allowed = true
+			}
+		}
+	}
+	if !allowed {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error": fmt.Sprintf("Transição de %s para %s não permitida", match.Status, target),
+		})
+		return nil, false
+	}
+
+	return &match, true
+}
+
+// applyTransition grava o novo status e executa os efeitos colaterais em uma
+// única transação. A partida é relida com bloqueio, para que duas requisições
+// simultâneas não apliquem a mesma transição (e liquidem as apostas duas vezes).
+// Retorna verdadeiro quando a transição foi gravada.
+func (c *MatchLifecycleController) applyTransition(ctx *gin.Context, match *model.Match, target string, effect sideEffect) bool {
+	previous := match.Status
+	summary := gin.H{}
+
+	err := c.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(match, match.ID).Error; err != nil {
+			return err
+		}
+		if match.Status != previous || !canTransition(match.Status, target) {
+			return errStaleTransition
+		}
+		match.Status = target
+		if effect != nil {
+			result, err := effect(tx, match)
+			if err != nil {
+				return err
+			}
+			for k, v := range result {
+				summary[k] = v
+			}
+		}
+		return tx.Save(match).Error
+	})
+	if err != nil {
+		if errors.Is(err, errStaleTransition) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Transição de %s para %s não permitida: %s", previous, target, err.Error())})
+			return false
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao alterar status da partida", "details": err.Error()})
+		return false
+	}
+
+	util.LogInfo(fmt.Sprintf("Partida %d: %s -> %s", match.ID, previous, target))
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"match":           toMatchResponse(*match),
+		"previous_status": previous,
+		"side_effects":    summary,
+	})
+	return true
+}
+
+// Kickoff inicia a partida e suspende os mercados pré-jogo
+func (c *MatchLifecycleController) Kickoff(ctx *gin.Context) {
+	match, ok := c.loadMatchFor(ctx, model.MatchStatusLive, model.MatchStatusScheduled)
+	if !ok {
+		return
+	}
+
+	if time.Now().Before(match.StartTime.Add(-kickoffTolerance)) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "A partida não pode ser iniciada com mais de uma hora de antecedência"})
+		return
+	}
+
+	c.applyTransition(ctx, match, model.MatchStatusLive, func(tx *gorm.DB, m *model.Match) (gin.H, error) {
+		m.MarketStatus = model.MarketStatusSuspended
+		m.HomeScore = 0
+		m.AwayScore = 0
+		return gin.H{"market_status": m.MarketStatus}, nil
+	})
+}
+
+// HalfTime marca o intervalo de uma partida em andamento
+func (c *MatchLifecycleController) HalfTime(ctx *gin.Context) {
+	match, ok := c.loadMatchFor(ctx, model.MatchStatusHalfTime)
+	if !ok {
+		return
+	}
+
+	c.applyTransition(ctx, match, model.MatchStatusHalfTime, nil)
+}
+
+// SecondHalf reinicia a partida após o intervalo
+func (c *MatchLifecycleController) SecondHalf(ctx *gin.Context) {
+	match, ok := c.loadMatchFor(ctx, model.MatchStatusLive, model.MatchStatusHalfTime)
+	if !ok {
+		return
+	}
+
+	c.applyTransition(ctx, match, model.MatchStatusLive, nil)
+}
+
+// Finish encerra a partida com o placar final e liquida as apostas
+func (c *MatchLifecycleController) Finish(ctx *gin.Context) {
+	match, ok := c.loadMatchFor(ctx, model.MatchStatusFinished)
+	if !ok {
+		return
+	}
+
+	var input model.MatchFinish
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	// Prorrogação e pênaltis só valem para confrontos de mata-mata empatados
+	final := *match
+	if input.HomeScore != nil {
+		final.HomeScore = *input.HomeScore
+	}
+	if input.AwayScore != nil {
+		final.AwayScore = *input.AwayScore
+	}
+	final.HomeExtraTimeScore = input.HomeExtraTimeScore
+	final.AwayExtraTimeScore = input.AwayExtraTimeScore
+	final.HomePenalties = input.HomePenalties
+	final.AwayPenalties = input.AwayPenalties
+	final.Status = model.MatchStatusFinished
+	if err := validateKnockoutFinish(c.DB, final); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	finished := c.applyTransition(ctx, match, model.MatchStatusFinished, func(tx *gorm.DB, m *model.Match) (gin.H, error) {
+		m.HomeScore = final.HomeScore
+		m.AwayScore = final.AwayScore
+		m.HomeExtraTimeScore = final.HomeExtraTimeScore
+		m.AwayExtraTimeScore = final.AwayExtraTimeScore
+		m.HomePenalties = final.HomePenalties
+		m.AwayPenalties = final.AwayPenalties
+		m.MarketStatus = model.MarketStatusClosed
+
+		// As apostas são liquidadas pelo placar do tempo regulamentar
+		settled, err := settleMatchBets(tx, *m)
+		if err != nil {
+			return nil, err
+		}
+		summary := gin.H{"market_status": m.MarketStatus, "bets_settled": settled}
+
+		served, err := serveSuspensions(tx, *m)
+		if err != nil {
+			return nil, err
+		}
+		summary["suspensions_served"] = served
+
+		bracket, err := advanceBracket(tx, *m)
+		if err != nil {
+			return nil, err
+		}
+		if bracket != nil {
+			summary["bracket"] = bracket
+		}
+		return summary, nil
+	})
+
+	// A classificação e o desempenho dos apostadores só mudam se o
+	// encerramento foi gravado
+	if finished {
+		invalidateStandings(c.Cache, match.TournamentID)
+		invalidateMatchAnalytics(c.DB, match.ID)
+	}
+}
+
+// Postpone adia uma partida agendada e suspende seus mercados
+func (c *MatchLifecycleController) Postpone(ctx *gin.Context) {
+	match, ok := c.loadMatchFor(ctx, model.MatchStatusPostponed)
+	if !ok {
+		return
+	}
+
+	input, ok := bindStatusChange(ctx)
+	if !ok {
+		return
+	}
+
+	c.applyTransition(ctx, match, model.MatchStatusPostponed, func(tx *gorm.DB, m *model.Match) (gin.H, error) {
+		m.StatusReason = input.Reason
+		m.MarketStatus = model.MarketStatusSuspended
+		return gin.H{"market_status": m.MarketStatus}, nil
+	})
+}
+
+// Reschedule define novas datas para uma partida adiada e reabre os mercados
+func (c *MatchLifecycleController) Reschedule(ctx *gin.Context) {
+	match, ok := c.loadMatchFor(ctx, model.MatchStatusScheduled)
+	if !ok {
+		return
+	}
+
+	var input model.MatchReschedule
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if input.StartTime.After(input.EndTime) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Data de início deve ser anterior à data de término"})
+		return
+	}
+
+	// Verifica se algum dos times já tem partida no novo horário
+	var existingMatch model.Match
+	if err := c.DB.Where(
+		"id != ? AND status NOT IN ? AND (home_team_id IN ? OR away_team_id IN ?) AND start_time <= ? AND end_time >= ?",
+		match.ID, []string{model.MatchStatusCancelled, model.MatchStatusPostponed},
+		[]uint{match.HomeTeamID, match.AwayTeamID}, []uint{match.HomeTeamID, match.AwayTeamID},
+		input.EndTime, input.StartTime,
+	).First(&existingMatch).Error; err == nil {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Já existe uma partida agendada para este time no mesmo horário"})
+		return
+	}
+
+	// Um novo horário além da janela permitida anula as apostas feitas para o horário original
+	delay := input.StartTime.Sub(match.StartTime)
+
+	voided := 0
+	rescheduled := c.applyTransition(ctx, match, model.MatchStatusScheduled, func(tx *gorm.DB, m *model.Match) (gin.H, error) {
+		if delay > c.PostponementWindow {
+			var err error
+			voided, err = voidMatchBets(tx, *m, "partida adiada além do prazo permitido")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		m.StartTime = input.StartTime
+		m.EndTime = input.EndTime
+		m.StatusReason = ""
+		m.MarketStatus = model.MarketStatusOpen
+		return gin.H{"market_status": m.MarketStatus, "bets_voided": voided}, nil
+	})
+	if rescheduled && voided > 0 {
+		invalidateMatchAnalytics(c.DB, match.ID)
+	}
+}
+
+// Cancel cancela a partida e anula as apostas pendentes
+func (c *MatchLifecycleController) Cancel(ctx *gin.Context) {
+	c.terminate(ctx, model.MatchStatusCancelled)
+}
+
+// Abandon encerra uma partida interrompida e anula as apostas pendentes
+func (c *MatchLifecycleController) Abandon(ctx *gin.Context) {
+	c.terminate(ctx, model.MatchStatusAbandoned)
+}
+
+// terminate leva a partida a um estado final sem resultado, anulando as apostas
+func (c *MatchLifecycleController) terminate(ctx *gin.Context, target string) {
+	match, ok := c.loadMatchFor(ctx, target)
+	if !ok {
+		return
+	}
+
+	input, ok := bindStatusChange(ctx)
+	if !ok {
+		return
+	}
+
+	closed := c.applyTransition(ctx, match, target, func(tx *gorm.DB, m *model.Match) (gin.H, error) {
+		voided, err := closeMatchWithoutResult(tx, m, target, input.Reason)
+		if err != nil {
+			return nil, err
+		}
+		return gin.H{"market_status": m.MarketStatus, "bets_voided": voided}, nil
+	})
+	if closed {
+		invalidateMatchAnalytics(c.DB, match.ID)
+	}
+}
+
+// closeMatchWithoutResult leva a partida a cancelled ou abandoned, fecha os
+// mercados e anula as apostas pendentes. Deve ser chamada dentro de uma transação.
+func closeMatchWithoutResult(tx *gorm.DB, match *model.Match, status, reason string) (int, error) {
+	match.Status = status
+	match.StatusReason = reason
+	match.MarketStatus = model.MarketStatusClosed
+	return voidMatchBets(tx, *match, reason)
+}
+
+// VoidExpiredPostponements anula as apostas de partidas que continuam adiadas
+// depois da janela permitida e fecha os seus mercados, para que não sejam
+// processadas de novo. Retorna quantas apostas foram anuladas.
+func VoidExpiredPostponements(db *gorm.DB, window time.Duration) (int, error) {
+	var matches []model.Match
+	if err := db.Where("status = ? AND market_status <> ? AND start_time < ?",
+		model.MatchStatusPostponed, model.MarketStatusClosed, time.Now().Add(-window)).
+		Find(&matches).Error; err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, match := range matches {
+		voided := 0
+		err := db.Transaction(func(tx *gorm.DB) error {
+			// A partida pode ter sido remarcada depois da busca
+			if err := lockMatch(tx, &match); err != nil {
+				return err
+			}
+			if match.Status != model.MatchStatusPostponed || match.MarketStatus == model.MarketStatusClosed {
+				return nil
+			}
+			var err error
+			voided, err = voidMatchBets(tx, match, "partida adiada além do prazo permitido")
+			if err != nil {
+				return err
+			}
+			return tx.Model(&match).Update("market_status", model.MarketStatusClosed).Error
+		})
+		if err != nil {
+			return total, err
+		}
+		total += voided
+		if voided > 0 {
+			invalidateMatchAnalytics(db, match.ID)
+		}
+	}
+
+	return total, nil
+}
+
+func bindStatusChange(ctx *gin.Context) (model.MatchStatusChange, bool) {
+	var input model.MatchStatusChange
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return input, false
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return input, false
+	}
+	return input, true
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{model.MatchStatusScheduled, model.MatchStatusLive, true},
+		{model.MatchStatusScheduled, model.MatchStatusPostponed, true},
+		{model.MatchStatusScheduled, model.MatchStatusCancelled, true},
+		{model.MatchStatusScheduled, model.MatchStatusFinished, false},
+		{model.MatchStatusScheduled, model.MatchStatusHalfTime, false},
+		{model.MatchStatusScheduled, model.MatchStatusAbandoned, false},
+		{model.MatchStatusLive, model.MatchStatusHalfTime, true},
+		{model.MatchStatusLive, model.MatchStatusFinished, true},
+		{model.MatchStatusLive, model.MatchStatusAbandoned, true},
+		{model.MatchStatusLive, model.MatchStatusCancelled, false},
+		{model.MatchStatusLive, model.MatchStatusPostponed, false},
+		{model.MatchStatusHalfTime, model.MatchStatusLive, true},
+		{model.MatchStatusHalfTime, model.MatchStatusAbandoned, true},
+		{model.MatchStatusHalfTime, model.MatchStatusFinished, false},
+		{model.MatchStatusPostponed, model.MatchStatusScheduled, true},
+		{model.MatchStatusPostponed, model.MatchStatusCancelled, true},
+		{model.MatchStatusPostponed, model.MatchStatusLive, false},
+		{model.MatchStatusFinished, model.MatchStatusLive, false},
+		{model.MatchStatusFinished, model.MatchStatusCancelled, false},
+		{model.MatchStatusCancelled, model.MatchStatusScheduled, false},
+		{model.MatchStatusAbandoned, model.MatchStatusLive, false},
+		{"", model.MatchStatusLive, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			if got := canTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("canTransition(%q, %q) = %v, esperado %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFinalStatusesHaveNoTransitions(t *testing.T) {
+	all := []string{
+		model.MatchStatusScheduled, model.MatchStatusLive, model.MatchStatusHalfTime,
+		model.MatchStatusFinished, model.MatchStatusPostponed, model.MatchStatusCancelled,
+		model.MatchStatusAbandoned,
+	}
+	for _, final := range []string{model.MatchStatusFinished, model.MatchStatusCancelled, model.MatchStatusAbandoned} {
+		for _, to := range all {
+			if canTransition(final, to) {
+				t.Errorf("%s é um estado final, mas permite ir para %s", final, to)
+			}
+		}
+	}
+
+	// Os demais status sempre têm uma saída
+	for _, from := range []string{model.MatchStatusScheduled, model.MatchStatusLive, model.MatchStatusHalfTime, model.MatchStatusPostponed} {
+		if len(matchTransitions[from]) == 0 {
+			t.Errorf("%s não tem transições", from)
+		}
+	}
+}
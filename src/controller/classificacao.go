@@ -0,0 +1,271 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+)
+
+// formLength é a quantidade de resultados recentes exibidos na classificação
+const formLength = 5
+
+// validTieBreakers são os critérios de desempate aceitos em Tournament.TieBreakers
+var validTieBreakers = map[string]bool{
+	model.TieBreakerHeadToHead:     true,
+	model.TieBreakerGoalDifference: true,
+	model.TieBreakerGoalsScored:    true,
+	model.TieBreakerWins:           true,
+}
+
+// parseTieBreakers converte a lista separada por vírgulas em critérios validados
+func parseTieBreakers(value string) ([]string, error) {
+	var criteria []string
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !validTieBreakers[item] {
+			return nil, fmt.Errorf("critério de desempate inválido: %s", item)
+		}
+		if seen[item] {
+			return nil, fmt.Errorf("critério de desempate repetido: %s", item)
+		}
+		seen[item] = true
+		criteria = append(criteria, item)
+	}
+	return criteria, nil
+}
+
+// standingsCacheKey retorna a chave de cache da classificação de um torneio
+func standingsCacheKey(tournamentID uint) string {
+	return fmt.Sprintf("%s%d", util.StandingsCacheKey, tournamentID)
+}
+
+// invalidateStandings descarta a classificação em cache de um torneio
+func invalidateStandings(cache util.Cache, tournamentID uint) {
+	if cache == nil {
+		return
+	}
+	if err := cache.Delete(standingsCacheKey(tournamentID)); err != nil {
+		util.LogError("Erro ao invalidar classificação em cache", err)
+	}
+}
+
+// computeStandings monta a classificação a partir das partidas encerradas do torneio
+func computeStandings(db *gorm.DB, tournament model.Tournament) (model.StandingsResponse, error) {
+	criteria, err := parseTieBreakers(tournament.TieBreakers)
+	if err != nil {
+		return model.StandingsResponse{}, err
+	}
+
+	var matches []model.Match
+	if err := db.Where("tournament_id = ? AND status = ?", tournament.ID, model.MatchStatusFinished).
+		Order("start_time").Find(&matches).Error; err != nil {
+		return model.StandingsResponse{}, err
+	}
+
+	// Times inscritos aparecem mesmo sem partidas disputadas
+	var teamIDs []uint
+	if err := db.Model(&model.TournamentTeam{}).Where("tournament_id = ?", tournament.ID).
+		Pluck("team_id", &teamIDs).Error; err != nil {
+		return model.StandingsResponse{}, err
+	}
+
+	rows := make(map[uint]*model.StandingRow)
+	addTeam := func(id uint) *model.StandingRow {
+		if row, ok := rows[id]; ok {
+			return row
+		}
+		row := &model.StandingRow{TeamID: id, Form: []string{}}
+		rows[id] = row
+		return row
+	}
+	for _, id := range teamIDs {
+		addTeam(id)
+	}
+
+	for _, match := range matches {
+		home := addTeam(match.HomeTeamID)
+		away := addTeam(match.AwayTeamID)
+		recordResult(home, match.HomeScore, match.AwayScore, tournament)
+		recordResult(away, match.AwayScore, match.HomeScore, tournament)
+	}
+
+	ids := make([]uint, 0, len(rows))
+	for id := range rows {
+		ids = append(ids, id)
+	}
+
+	var teams []model.Team
+	if len(ids) > 0 {
+		if err := db.Where("id IN ?", ids).Find(&teams).Error; err != nil {
+			return model.StandingsResponse{}, err
+		}
+	}
+	for _, team := range teams {
+		rows[team.ID].TeamName = team.Name
+	}
+
+	table := make([]model.StandingRow, 0, len(rows))
+	for _, id := range rankTeams(ids, rows, matches, criteria, tournament) {
+		row := *rows[id]
+		row.Position = len(table) + 1
+		if len(row.Form) > formLength {
+			row.Form = row.Form[len(row.Form)-formLength:]
+		}
+		table = append(table, row)
+	}
+
+	return model.StandingsResponse{
+		TournamentID: tournament.ID,
+		PointsWin:    tournament.PointsWin,
+		PointsDraw:   tournament.PointsDraw,
+		PointsLoss:   tournament.PointsLoss,
+		TieBreakers:  criteria,
+		Table:        table,
+		GeneratedAt:  time.Now(),
+	}, nil
+}
+
+// recordResult soma uma partida na linha do time
+func recordResult(row *model.StandingRow, scored, conceded int, tournament model.Tournament) {
+	row.Played++
+	row.GoalsFor += scored
+	row.GoalsAgainst += conceded
+	row.GoalDifference = row.GoalsFor - row.GoalsAgainst
+
+	switch {
+	case scored > conceded:
+		row.Wins++
+		row.Points += tournament.PointsWin
+		row.Form = append(row.Form, "W")
+	case scored < conceded:
+		row.Losses++
+		row.Points += tournament.PointsLoss
+		row.Form = append(row.Form, "L")
+	default:
+		row.Draws++
+		row.Points += tournament.PointsDraw
+		row.Form = append(row.Form, "D")
+	}
+}
+
+// rankTeams ordena os times por pontos e aplica os critérios de desempate em
+// sequência. O confronto direto considera apenas as partidas entre os times
+// que continuam empatados naquele ponto.
+func rankTeams(ids []uint, rows map[uint]*model.StandingRow, matches []model.Match, criteria []string, tournament model.Tournament) []uint {
+	points := func(group []uint) map[uint][2]int {
+		keys := make(map[uint][2]int, len(group))
+		for _, id := range group {
+			keys[id] = [2]int{rows[id].Points, 0}
+		}
+		return keys
+	}
+	return rankGroup(ids, rows, matches, append([]string{"points"}, criteria...), tournament, points)
+}
+
+type rankKeyFunc func(group []uint) map[uint][2]int
+
+func rankGroup(group []uint, rows map[uint]*model.StandingRow, matches []model.Match, criteria []string, tournament model.Tournament, keyFn rankKeyFunc) []uint {
+	if len(group) <= 1 {
+		return group
+	}
+	if len(criteria) == 0 || keyFn == nil {
+		// Sem critérios restantes, ordena por nome para manter o resultado estável
+		sort.SliceStable(group, func(i, j int) bool {
+			if rows[group[i]].TeamName != rows[group[j]].TeamName {
+				return rows[group[i]].TeamName < rows[group[j]].TeamName
+			}
+			return group[i] < group[j]
+		})
+		return group
+	}
+
+	keys := keyFn(group)
+	sort.SliceStable(group, func(i, j int) bool {
+		a, b := keys[group[i]], keys[group[j]]
+		if a[0] != b[0] {
+			return a[0] > b[0]
+		}
+		return a[1] > b[1]
+	})
+
+	var nextKey rankKeyFunc
+	if len(criteria) > 1 {
+		nextKey = tieBreakerKey(criteria[1], rows, matches, tournament)
+	}
+
+	ranked := make([]uint, 0, len(group))
+	for start := 0; start < len(group); {
+		end := start + 1
+		for end < len(group) && keys[group[end]] == keys[group[start]] {
+			end++
+		}
+		tied := append([]uint(nil), group[start:end]...)
+		ranked = append(ranked, rankGroup(tied, rows, matches, criteria[1:], tournament, nextKey)...)
+		start = end
+	}
+	return ranked
+}
+
+// tieBreakerKey retorna a função que calcula o valor de um critério de desempate
+func tieBreakerKey(criterion string, rows map[uint]*model.StandingRow, matches []model.Match, tournament model.Tournament) rankKeyFunc {
+	switch criterion {
+	case model.TieBreakerGoalDifference:
+		return func(group []uint) map[uint][2]int {
+			keys := make(map[uint][2]int, len(group))
+			for _, id := range group {
+				keys[id] = [2]int{rows[id].GoalDifference, 0}
+			}
+			return keys
+		}
+	case model.TieBreakerGoalsScored:
+		return func(group []uint) map[uint][2]int {
+			keys := make(map[uint][2]int, len(group))
+			for _, id := range group {
+				keys[id] = [2]int{rows[id].GoalsFor, 0}
+			}
+			return keys
+		}
+	case model.TieBreakerWins:
+		return func(group []uint) map[uint][2]int {
+			keys := make(map[uint][2]int, len(group))
+			for _, id := range group {
+				keys[id] = [2]int{rows[id].Wins, 0}
+			}
+			return keys
+		}
+	case model.TieBreakerHeadToHead:
+		// Pontos e saldo de gols nas partidas entre os times empatados
+		return func(group []uint) map[uint][2]int {
+			inGroup := make(map[uint]bool, len(group))
+			for _, id := range group {
+				inGroup[id] = true
+			}
+			mini := make(map[uint]*model.StandingRow, len(group))
+			for _, id := range group {
+				mini[id] = &model.StandingRow{TeamID: id}
+			}
+			for _, match := range matches {
+				if !inGroup[match.HomeTeamID] || !inGroup[match.AwayTeamID] {
+					continue
+				}
+				recordResult(mini[match.HomeTeamID], match.HomeScore, match.AwayScore, tournament)
+				recordResult(mini[match.AwayTeamID], match.AwayScore, match.HomeScore, tournament)
+			}
+			keys := make(map[uint][2]int, len(group))
+			for _, id := range group {
+				keys[id] = [2]int{mini[id].Points, mini[id].GoalDifference}
+			}
+			return keys
+		}
+	}
+	return nil
+}
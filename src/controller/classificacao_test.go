@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+)
+
+// game monta uma partida encerrada entre dois times
+func game(home, away uint, homeScore, awayScore int) model.Match {
+	return model.Match{HomeTeamID: home, AwayTeamID: away, HomeScore: homeScore, AwayScore: awayScore}
+}
+
+func TestRankTeams(t *testing.T) {
+	// 1 e 2 empatam em 4 pontos, com 1 vencendo o confronto direto e 2 com
+	// saldo melhor; 3 e 4 empatam em 1 ponto sem terem se enfrentado
+	fourTeams := []model.Match{
+		game(1, 2, 1, 0),
+		game(2, 3, 5, 0),
+		game(1, 3, 1, 1),
+		game(2, 4, 0, 0),
+	}
+	// Triangular: cada time vence uma e perde outra
+	cycle := []model.Match{
+		game(1, 2, 1, 0),
+		game(2, 3, 5, 0),
+		game(3, 1, 1, 0),
+	}
+
+	tests := []struct {
+		name        string
+		matches     []model.Match
+		tieBreakers string
+		pointsDraw  int
+		want        []uint
+	}{
+		{"confronto direto antes do saldo", fourTeams, "head_to_head,goal_difference", 1, []uint{1, 2, 4, 3}},
+		{"saldo antes do confronto direto", fourTeams, "goal_difference,head_to_head", 1, []uint{2, 1, 4, 3}},
+		{"confronto direto sem jogo entre os empatados passa ao próximo critério", fourTeams, "head_to_head,goals_scored", 1, []uint{1, 2, 3, 4}},
+		{"triangular decidida pelo saldo entre os empatados", cycle, "head_to_head", 1, []uint{2, 1, 3}},
+		{"sem critérios restantes ordena pelo nome", cycle, "", 1, []uint{1, 2, 3}},
+		{"empate sem pontos", fourTeams, "goal_difference", 0, []uint{2, 1, 4, 3}},
+	}
+
+	names := map[uint]string{1: "Alfa", 2: "Beta", 3: "Gama", 4: "Delta"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tournament := model.Tournament{PointsWin: 3, PointsDraw: tt.pointsDraw, PointsLoss: 0}
+			criteria, err := parseTieBreakers(tt.tieBreakers)
+			if err != nil {
+				t.Fatalf("parseTieBreakers: %v", err)
+			}
+
+			rows := make(map[uint]*model.StandingRow)
+			var ids []uint
+			row := func(id uint) *model.StandingRow {
+				if rows[id] == nil {
+					rows[id] = &model.StandingRow{TeamID: id, TeamName: names[id]}
+					ids = append(ids, id)
+				}
+				return rows[id]
+			}
+			for _, m := range tt.matches {
+				recordResult(row(m.HomeTeamID), m.HomeScore, m.AwayScore, tournament)
+				recordResult(row(m.AwayTeamID), m.AwayScore, m.HomeScore, tournament)
+			}
+
+			if got := rankTeams(ids, rows, tt.matches, criteria, tournament); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("classificação %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/config"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// minOfferedProbability descarta opções improváveis demais para serem oferecidas
+const minOfferedProbability = 0.001
+
+// errNoDrafts indica que não há rascunhos para revisar
+var errNoDrafts = errors.New("nenhuma cotação em rascunho encontrada")
+
+type OddsController struct {
+	DB *gorm.DB
+	// Margin é a margem padrão aplicada às cotações sugeridas
+	Margin float64
+}
+
+func NewOddsController(db *gorm.DB) *OddsController {
+	return &OddsController{
+		DB:     db,
+		Margin: config.LoadConfig().Betting.OddsMargin,
+	}
+}
+
+// sameSelection informa se duas cotações se referem à mesma opção de mercado
+func sameSelection(a, b model.MarketOdds) bool {
+	if a.Market != b.Market || a.Selection != b.Selection {
+		return false
+	}
+	if a.Line == nil || b.Line == nil {
+		return a.Line == nil && b.Line == nil
+	}
+	return *a.Line == *b.Line
+}
+
+// loadMatch busca a partida da rota
+func (c *OddsController) loadMatch(ctx *gin.Context) (model.Match, bool) {
+	var match model.Match
+	if err := c.DB.First(&match, ctx.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Partida não encontrada"})
+			return match, false
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar partida"})
+		return match, false
+	}
+	return match, true
+}
+
+// CompileOdds calcula as cotações sugeridas de uma partida a partir do
+// histórico de resultados e as grava como rascunho, substituindo os
+// rascunhos anteriores. Com dry_run, apenas retorna o cálculo.
+func (c *OddsController) CompileOdds(ctx *gin.Context) {
+	match, ok := c.loadMatch(ctx)
+	if !ok {
+		return
+	}
+	if match.Status != model.MatchStatusScheduled && match.Status != model.MatchStatusPostponed {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Só é possível precificar partidas que ainda não começaram"})
+		return
+	}
+
+	var input model.OddsCompile
+	if err := ctx.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	margin := c.Margin
+	if input.Margin != nil {
+		margin = *input.Margin
+	}
+
+	now := time.Now()
+	ratings, err := loadRatings(c.DB, match, now)
+	if err != nil {
+		if errors.Is(err, errNoHistory) {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar histórico de partidas"})
+		return
+	}
+
+	lambdaHome, lambdaAway := ratings.expectedGoals(match.HomeTeamID, match.AwayTeamID)
+	homeAttack, homeDefence, homeMatches := ratings.strength(match.HomeTeamID)
+	awayAttack, awayDefence, awayMatches := ratings.strength(match.AwayTeamID)
+	response := model.OddsCompileResponse{
+		MatchID:           match.ID,
+		HomeExpectedGoals: math.Round(lambdaHome*1000) / 1000,
+		AwayExpectedGoals: math.Round(lambdaAway*1000) / 1000,
+		Home:              model.TeamRatingResponse{TeamID: match.HomeTeamID, Attack: homeAttack, Defence: homeDefence, Matches: homeMatches},
+		Away:              model.TeamRatingResponse{TeamID: match.AwayTeamID, Attack: awayAttack, Defence: awayDefence, Matches: awayMatches},
+		MatchesUsed:       ratings.MatchesUsed,
+		Margin:            margin,
+		DryRun:            input.DryRun,
+	}
+
+	userID := ctx.GetUint("user_id")
+	for _, market := range marketProbabilities(scoreMatrix(lambdaHome, lambdaAway)) {
+		for _, s := range market {
+			if s.Probability < minOfferedProbability {
+				continue
+			}
+			fair, offered := applyMargin(s.Probability, margin)
+			response.Odds = append(response.Odds, model.MarketOdds{
+				MatchID:     match.ID,
+				Market:      s.Market,
+				Selection:   s.Selection,
+				Line:        s.Line,
+				Probability: math.Round(s.Probability*10000) / 10000,
+				FairOdds:    fair,
+				Odds:        offered,
+				Margin:      margin,
+				Status:      model.OddsStatusDraft,
+				CreatedBy:   userID,
+			})
+		}
+	}
+
+	if input.DryRun {
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+
+	err = c.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.MarketOdds{}).
+			Where("match_id = ? AND status = ?", match.ID, model.OddsStatusDraft).
+			Update("status", model.OddsStatusSuperseded).Error; err != nil {
+			return err
+		}
+		return tx.Create(&response.Odds).Error
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gravar cotações", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ListOdds retorna as cotações de uma partida, com filtros opcionais de status
+// e mercado. As cotações aprovadas saem com o fator de risco da opção já
+// aplicado, que é o preço aceito na aposta.
+func (c *OddsController) ListOdds(ctx *gin.Context) {
+	match, ok := c.loadMatch(ctx)
+	if !ok {
+		return
+	}
+
+	query := c.DB.Where("match_id = ?", match.ID)
+	if status := ctx.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if market := ctx.Query("market"); market != "" {
+		query = query.Where("market = ?", market)
+	}
+
+	odds := []model.MarketOdds{}
+	if err := query.Order("market, id").Find(&odds).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar cotações"})
+		return
+	}
+
+	var positions []model.RiskPosition
+	if err := c.DB.Where("match_id = ? AND odds_factor <> 1", match.ID).Find(&positions).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar posições de risco"})
+		return
+	}
+	factors := make(map[riskKey]float64, len(positions))
+	for _, p := range positions {
+		factors[riskKey{p.Market, p.Selection}] = p.OddsFactor
+	}
+	for i := range odds {
+		if odds[i].Status != model.OddsStatusApproved {
+			continue
+		}
+		if factor, ok := factors[riskKey{odds[i].Market, riskSelection(odds[i].Selection, odds[i].Line)}]; ok {
+			odds[i].Odds = adjustedOdds(odds[i].Odds, factor)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, odds)
+}
+
+// reviewDrafts carrega e trava os rascunhos da partida a revisar
+func reviewDrafts(tx *gorm.DB, matchID uint, ids []uint) ([]model.MarketOdds, error) {
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("match_id = ? AND status = ?", matchID, model.OddsStatusDraft)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	var drafts []model.MarketOdds
+	if err := query.Find(&drafts).Error; err != nil {
+		return nil, err
+	}
+	if len(drafts) == 0 {
+		return nil, errNoDrafts
+	}
+	return drafts, nil
+}
+
+// bindReview lê o corpo opcional da revisão
+func bindReview(ctx *gin.Context) (model.OddsReview, bool) {
+	var input model.OddsReview
+	if err := ctx.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return input, false
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return input, false
+	}
+	return input, true
+}
+
+// ApproveOdds publica os rascunhos da partida, com ajustes opcionais do
+// trader. A cotação aprovada substitui a anterior da mesma opção.
+func (c *OddsController) ApproveOdds(ctx *gin.Context) {
+	match, ok := c.loadMatch(ctx)
+	if !ok {
+		return
+	}
+	input, ok := bindReview(ctx)
+	if !ok {
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	now := time.Now()
+	var approved []model.MarketOdds
+	err := c.DB.Transaction(func(tx *gorm.DB) error {
+		drafts, err := reviewDrafts(tx, match.ID, input.IDs)
+		if err != nil {
+			return err
+		}
+
+		var current []model.MarketOdds
+		if err := tx.Where("match_id = ? AND status = ?", match.ID, model.OddsStatusApproved).
+			Find(&current).Error; err != nil {
+			return err
+		}
+
+		for _, draft := range drafts {
+			if odds, ok := input.Odds[draft.ID]; ok {
+				// A margem passa a refletir a cotação ajustada pelo trader
+				draft.Odds = odds
+				draft.Margin = math.Max(math.Round((1/(draft.Probability*odds)-1)*10000)/10000, 0)
+			}
+			for _, old := range current {
+				if sameSelection(old, draft) {
+					if err := tx.Model(&old).Update("status", model.OddsStatusSuperseded).Error; err != nil {
+						return err
+					}
+				}
+			}
+			draft.Status = model.OddsStatusApproved
+			draft.ReviewedBy = &userID
+			draft.ReviewedAt = &now
+			if err := tx.Save(&draft).Error; err != nil {
+				return err
+			}
+			approved = append(approved, draft)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errNoDrafts) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao aprovar cotações", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"match_id": match.ID, "approved": len(approved), "data": approved})
+}
+
+// RejectOdds descarta rascunhos da partida
+func (c *OddsController) RejectOdds(ctx *gin.Context) {
+	match, ok := c.loadMatch(ctx)
+	if !ok {
+		return
+	}
+	input, ok := bindReview(ctx)
+	if !ok {
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	now := time.Now()
+	rejected := 0
+	err := c.DB.Transaction(func(tx *gorm.DB) error {
+		drafts, err := reviewDrafts(tx, match.ID, input.IDs)
+		if err != nil {
+			return err
+		}
+		ids := make([]uint, 0, len(drafts))
+		for _, d := range drafts {
+			ids = append(ids, d.ID)
+		}
+		rejected = len(ids)
+		return tx.Model(&model.MarketOdds{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":      model.OddsStatusRejected,
+			"reviewed_by": userID,
+			"reviewed_at": now,
+		}).Error
+	})
+	if err != nil {
+		if errors.Is(err, errNoDrafts) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao rejeitar cotações", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"match_id": match.ID, "rejected": rejected})
+}
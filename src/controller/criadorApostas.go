@@ -0,0 +1,305 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/config"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+)
+
+var (
+	// errContradictorySelections indica palpites que não podem acertar juntos
+	errContradictorySelections = errors.New("os palpites da combinação se contradizem")
+	// errUnlikelySelections indica uma combinação improvável demais para ser oferecida
+	errUnlikelySelections = errors.New("a combinação é improvável demais para ser oferecida")
+)
+
+// builderLeg converte um palpite da combinação em aposta simples, para ser
+// avaliado com as mesmas regras de liquidação
+func builderLeg(s model.BetSelection) model.Bet {
+	return model.Bet{BetType: s.BetType, Selection: s.Selection, Line: s.Line}
+}
+
+// builderMarket identifica o mercado de um palpite; uma combinação não pode
+// ter dois palpites do mesmo mercado e linha
+func builderMarket(s model.BetBuilderSelection) string {
+	market, _ := betMarket(s.BetType, s.Selection)
+	if market == model.MarketMatchResult || s.Line == nil {
+		return market
+	}
+	return riskBook(market, s.Selection, s.Line)
+}
+
+// validateBuilderSelections verifica cada palpite da combinação. Só são
+// aceitos mercados decididos pelo placar final e linhas de meio gol, que não
+// têm devolução, para que a combinação seja sempre ganha ou perdida.
+func validateBuilderSelections(selections []model.BetBuilderSelection) error {
+	markets := make(map[string]bool, len(selections))
+	for _, s := range selections {
+		if err := validateBetSelection(s.BetType, s.Selection, s.Line); err != nil {
+			return err
+		}
+		if s.Line != nil && math.Mod(math.Abs(*s.Line)*2, 2) != 1 {
+			return fmt.Errorf("o criador de apostas só aceita linhas de meio gol (1.5, 2.5, -0.5...)")
+		}
+		market := builderMarket(s)
+		if markets[market] {
+			return fmt.Errorf("a combinação tem mais de um palpite no mesmo mercado")
+		}
+		markets[market] = true
+	}
+	return nil
+}
+
+// builderProbability soma na matriz de placares a probabilidade dos placares
+// em que todos os palpites acertam. Também retorna a probabilidade de cada
+// palpite isolado.
+func builderProbability(matrix [][]float64, selections []model.BetBuilderSelection) (float64, []float64) {
+	joint := 0.0
+	single := make([]float64, len(selections))
+	for h := range matrix {
+		for a, p := range matrix[h] {
+			score := model.Match{HomeScore: h, AwayScore: a}
+			all := true
+			for i, s := range selections {
+				leg := model.BetSelection{BetType: s.BetType, Selection: s.Selection, Line: s.Line}
+				status, _ := betOutcome(builderLeg(leg), score, nil, nil)
+				if status == model.BetStatusWon {
+					single[i] += p
+				} else {
+					all = false
+				}
+			}
+			if all {
+				joint += p
+			}
+		}
+	}
+	return joint, single
+}
+
+// quoteBetBuilder precifica uma combinação de palpites da partida
+func quoteBetBuilder(db *gorm.DB, match model.Match, selections []model.BetBuilderSelection) (model.BetBuilderQuoteResponse, error) {
+	quote := model.BetBuilderQuoteResponse{
+		MatchID:    match.ID,
+		Selections: selections,
+		Margin:     bettingConfig.OddsMargin,
+	}
+
+	ratings, err := loadRatings(db, match, time.Now())
+	if err != nil {
+		return quote, err
+	}
+	lambdaHome, lambdaAway := ratings.expectedGoals(match.HomeTeamID, match.AwayTeamID)
+	joint, single := builderProbability(scoreMatrix(lambdaHome, lambdaAway), selections)
+	if joint == 0 {
+		return quote, errContradictorySelections
+	}
+	if joint < minOfferedProbability {
+		return quote, errUnlikelySelections
+	}
+
+	quote.Probability = math.Round(joint*10000) / 10000
+	quote.FairOdds, quote.Odds = applyMargin(joint, quote.Margin)
+	naive := 1.0
+	for _, p := range single {
+		naive /= p
+	}
+	quote.NaiveOdds = math.Round(naive*100) / 100
+	return quote, nil
+}
+
+// bettableMatch busca a partida e verifica se ela aceita apostas
+func bettableMatch(c *gin.Context, matchID uint) (model.Match, bool) {
+	var match model.Match
+	if err := config.DB.First(&match, matchID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Partida não encontrada"})
+		return match, false
+	}
+	if match.Status != model.MatchStatusScheduled || match.MarketStatus == model.MarketStatusSuspended || match.MarketStatus == model.MarketStatusClosed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A partida não está disponível para apostas"})
+		return match, false
+	}
+	if match.StartTime.Before(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A partida já começou"})
+		return match, false
+	}
+	return match, true
+}
+
+// respondQuoteError traduz os erros de precificação da combinação
+func respondQuoteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errContradictorySelections), errors.Is(err, errUnlikelySelections):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, errNoHistory):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao precificar combinação", "details": err.Error()})
+	}
+}
+
+// QuoteBetBuilder retorna a cotação de uma combinação de palpites da mesma
+// partida, sem criar a aposta
+func QuoteBetBuilder(c *gin.Context) {
+	var input model.BetBuilderQuote
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := validateBuilderSelections(input.Selections); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	match, ok := bettableMatch(c, input.MatchID)
+	if !ok {
+		return
+	}
+
+	quote, err := quoteBetBuilder(config.DB, match, input.Selections)
+	if err != nil {
+		respondQuoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+// CreateBuilderBet cria uma aposta combinada com palpites da mesma partida,
+// cotada pela probabilidade conjunta dos palpites
+func CreateBuilderBet(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var input model.BetBuilderCreate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := validateBuilderSelections(input.Selections); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user model.User
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		return
+	}
+	if user.Balance < input.Amount {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Saldo insuficiente para realizar a aposta"})
+		return
+	}
+
+	match, ok := bettableMatch(c, input.MatchID)
+	if !ok {
+		return
+	}
+
+	// A combinação conta como a aposta ativa do usuário na partida
+	var existingBet model.Bet
+	if err := config.DB.Where("user_id = ? AND match_id = ? AND status = ?", userID, match.ID, model.BetStatusPending).First(&existingBet).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Você já tem uma aposta ativa para esta partida"})
+		return
+	}
+
+	quote, err := quoteBetBuilder(config.DB, match, input.Selections)
+	if err != nil {
+		respondQuoteError(c, err)
+		return
+	}
+	if input.Odds > quote.Odds {
+		c.JSON(http.StatusConflict, gin.H{"error": "A cotação desta combinação mudou", "current_odds": quote.Odds})
+		return
+	}
+
+	maxStake, limited, err := maxAllowedStake(config.DB, user, match, model.BetTypeBetBuilder, quote.Odds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar limites de aposta", "details": err.Error()})
+		return
+	}
+	if limited && input.Amount > maxStake {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     fmt.Sprintf("O valor máximo permitido para esta aposta é R$ %.2f", maxStake),
+			"max_stake": maxStake,
+		})
+		return
+	}
+
+	message, details, err := checkBetRisk(config.DB, match.ID, model.BetTypeBetBuilder, "", nil, input.Amount, quote.Odds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar risco da aposta", "details": err.Error()})
+		return
+	}
+	if message != "" {
+		response := gin.H{"error": message}
+		for k, v := range details {
+			response[k] = v
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+
+	bet := model.Bet{
+		UserID:   userID,
+		MatchID:  match.ID,
+		BetType:  model.BetTypeBetBuilder,
+		Amount:   input.Amount,
+		Odds:     quote.Odds,
+		BetLimit: maxStake,
+		Status:   model.BetStatusPending,
+	}
+	for _, s := range input.Selections {
+		bet.Selections = append(bet.Selections, model.BetSelection{BetType: s.BetType, Selection: s.Selection, Line: s.Line})
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Os palpites são gravados junto com a aposta
+		if err := tx.Create(&bet).Error; err != nil {
+			return err
+		}
+		if err := debitBalance(tx, userID, &bet.ID, model.LedgerTypeBetStake, bet.Amount,
+			fmt.Sprintf("Aposta combinada #%d na partida #%d", bet.ID, bet.MatchID)); err != nil {
+			return err
+		}
+		return refreshRiskPositions(tx, bet.MatchID)
+	})
+	if err != nil {
+		if errors.Is(err, errInsufficientBalance) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Saldo insuficiente para realizar a aposta"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar aposta", "details": err.Error()})
+		return
+	}
+	invalidateUserAnalytics(bet.UserID)
+
+	c.JSON(http.StatusCreated, model.BetResponse{
+		ID:         bet.ID,
+		UserID:     bet.UserID,
+		MatchID:    bet.MatchID,
+		BetType:    bet.BetType,
+		Amount:     bet.Amount,
+		Odds:       bet.Odds,
+		Status:     bet.Status,
+		BetLimit:   bet.BetLimit,
+		Selections: bet.Selections,
+		CreatedAt:  bet.CreatedAt,
+		UpdatedAt:  bet.UpdatedAt,
+	})
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+)
+
+// builderSelection monta um palpite do criador de apostas
+func builderSelection(betType, selection string, line float64) model.BetBuilderSelection {
+	s := model.BetBuilderSelection{BetType: betType, Selection: selection}
+	if selection == model.SelectionOver || selection == model.SelectionUnder || betType == model.BetTypeAsianHandicap {
+		s.Line = &line
+	}
+	return s
+}
+
+func TestBuilderProbability(t *testing.T) {
+	// Matriz de placares até 2 gols por time; soma 1
+	matrix := [][]float64{
+		{0.10, 0.08, 0.02},
+		{0.15, 0.12, 0.05},
+		{0.20, 0.18, 0.10},
+	}
+	win := builderSelection(model.BetTypeWin, "", 0)
+	under15 := builderSelection(model.BetTypeOverUnder, model.SelectionUnder, 1.5)
+	under05 := builderSelection(model.BetTypeOverUnder, model.SelectionUnder, 0.5)
+	over25 := builderSelection(model.BetTypeTotalGoals, model.SelectionOver, 2.5)
+	btts := builderSelection(model.BetTypeBothTeamsScore, "", 0)
+
+	tests := []struct {
+		name       string
+		selections []model.BetBuilderSelection
+		wantJoint  float64
+		wantSingle []float64
+	}{
+		// Só o 1 x 0 atende aos dois palpites
+		{"mandante e menos de 1.5 gol", []model.BetBuilderSelection{win, under15}, 0.15, []float64{0.53, 0.33}},
+		{"mandante sozinho", []model.BetBuilderSelection{win}, 0.53, []float64{0.53}},
+		{"ambos marcam e mais de 2.5 gols", []model.BetBuilderSelection{btts, over25}, 0.33, []float64{0.45, 0.33}},
+		{"mandante sem gols é contraditório", []model.BetBuilderSelection{win, under05}, 0, []float64{0.53, 0.10}},
+		{"ambos marcam com menos de 1.5 gol é contraditório", []model.BetBuilderSelection{btts, under15}, 0, []float64{0.45, 0.33}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joint, single := builderProbability(matrix, tt.selections)
+			if math.Abs(joint-tt.wantJoint) > 1e-9 {
+				t.Errorf("probabilidade conjunta %v, esperado %v", joint, tt.wantJoint)
+			}
+			for i := range tt.wantSingle {
+				if math.Abs(single[i]-tt.wantSingle[i]) > 1e-9 {
+					t.Errorf("palpite %d com probabilidade %v, esperado %v", i+1, single[i], tt.wantSingle[i])
+				}
+			}
+			// A combinação nunca é mais provável que o palpite menos provável
+			for i, p := range single {
+				if joint > p+1e-9 {
+					t.Errorf("probabilidade conjunta %v maior que a do palpite %d (%v)", joint, i+1, p)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateBuilderSelections(t *testing.T) {
+	tests := []struct {
+		name       string
+		selections []model.BetBuilderSelection
+		wantErr    bool
+	}{
+		{"mercados diferentes", []model.BetBuilderSelection{
+			builderSelection(model.BetTypeWin, "", 0),
+			builderSelection(model.BetTypeOverUnder, model.SelectionUnder, 1.5),
+		}, false},
+		{"linhas diferentes do mesmo mercado", []model.BetBuilderSelection{
+			builderSelection(model.BetTypeOverUnder, model.SelectionOver, 1.5),
+			builderSelection(model.BetTypeTotalGoals, model.SelectionUnder, 3.5),
+		}, false},
+		{"handicap com resultado", []model.BetBuilderSelection{
+			builderSelection(model.BetTypeWin, "", 0),
+			builderSelection(model.BetTypeAsianHandicap, model.SelectionHome, -1.5),
+		}, false},
+		{"dois palpites de resultado", []model.BetBuilderSelection{
+			builderSelection(model.BetTypeWin, "", 0),
+			builderSelection(model.BetTypeDraw, "", 0),
+		}, true},
+		{"mais e menos na mesma linha", []model.BetBuilderSelection{
+			builderSelection(model.BetTypeOverUnder, model.SelectionOver, 2.5),
+			builderSelection(model.BetTypeTotalGoals, model.SelectionUnder, 2.5),
+		}, true},
+		{"handicaps opostos na mesma linha", []model.BetBuilderSelection{
+			builderSelection(model.BetTypeAsianHandicap, model.SelectionHome, -0.5),
+			builderSelection(model.BetTypeAsianHandicap, model.SelectionAway, 0.5),
+		}, true},
+		{"linha inteira", []model.BetBuilderSelection{
+			builderSelection(model.BetTypeWin, "", 0),
+			builderSelection(model.BetTypeOverUnder, model.SelectionOver, 2),
+		}, true},
+		{"linha de quarto de gol", []model.BetBuilderSelection{
+			builderSelection(model.BetTypeWin, "", 0),
+			builderSelection(model.BetTypeOverUnder, model.SelectionOver, 2.25),
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBuilderSelections(tt.selections)
+			if tt.wantErr && err == nil {
+				t.Errorf("validateBuilderSelections aceitou a combinação")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("validateBuilderSelections: %v", err)
+			}
+		})
+	}
+}
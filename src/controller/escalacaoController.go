@@ -0,0 +1,414 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+)
+
+// maxSubstitutions é a quantidade máxima de substituições por time em uma partida
+const maxSubstitutions = 5
+
+type MatchLineupController struct {
+	DB *gorm.DB
+}
+
+func NewMatchLineupController(db *gorm.DB) *MatchLineupController {
+	return &MatchLineupController{DB: db}
+}
+
+// formationSlots converte um esquema tático como 4-2-3-1 na quantidade de
+// defensores, meio-campistas e atacantes. O primeiro número é a defesa, o
+// último o ataque e os demais somam o meio-campo.
+func formationSlots(formation string) (map[string]int, error) {
+	parts := strings.Split(formation, "-")
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("esquema tático inválido: %s", formation)
+	}
+
+	lines := make([]int, len(parts))
+	total := 0
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("esquema tático inválido: %s", formation)
+		}
+		lines[i] = n
+		total += n
+	}
+	if total != 10 {
+		return nil, fmt.Errorf("o esquema tático %s não soma 10 jogadores de linha", formation)
+	}
+
+	midfield := 0
+	for _, n := range lines[1 : len(lines)-1] {
+		midfield += n
+	}
+	return map[string]int{
+		model.PositionGoalkeeper: 1,
+		model.PositionDefender:   lines[0],
+		model.PositionMidfielder: midfield,
+		model.PositionForward:    lines[len(lines)-1],
+	}, nil
+}
+
+// GetLineups retorna as escalações dos dois times da partida
+func (c *MatchLineupController) GetLineups(ctx *gin.Context) {
+	var match model.Match
+	if err := c.DB.First(&match, ctx.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Partida não encontrada"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar partida"})
+		return
+	}
+
+	lineups := []model.LineupResponse{}
+	for _, teamID := range []uint{match.HomeTeamID, match.AwayTeamID} {
+		lineup, found, err := loadLineup(c.DB, match, teamID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar escalação"})
+			return
+		}
+		if found {
+			lineups = append(lineups, lineup)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, lineups)
+}
+
+// SubmitLineup cadastra ou substitui a escalação de um time antes do início da partida
+func (c *MatchLineupController) SubmitLineup(ctx *gin.Context) {
+	var match model.Match
+	if err := c.DB.First(&match, ctx.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Partida não encontrada"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar partida"})
+		return
+	}
+
+	if match.Status != model.MatchStatusScheduled && match.Status != model.MatchStatusPostponed {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "A escalação não pode ser alterada depois do início da partida"})
+		return
+	}
+
+	var input model.LineupSubmit
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if input.TeamID != match.HomeTeamID && input.TeamID != match.AwayTeamID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "O time não participa desta partida"})
+		return
+	}
+
+	rows, err := c.buildLineup(match, input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = c.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("match_id = ? AND team_id = ?", match.ID, input.TeamID).
+			Delete(&model.MatchLineupPlayer{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+
+		// O esquema tático fica registrado na relação partida-clube
+		var matchTeam model.MatchTeam
+		err := tx.Where("match_id = ? AND team_id = ?", match.ID, input.TeamID).First(&matchTeam).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		matchTeam.MatchID = match.ID
+		matchTeam.TeamID = input.TeamID
+		matchTeam.IsHome = input.TeamID == match.HomeTeamID
+		matchTeam.Formation = input.Formation
+		return tx.Save(&matchTeam).Error
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar escalação", "details": err.Error()})
+		return
+	}
+
+	lineup, _, err := loadLineup(c.DB, match, input.TeamID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar escalação"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, lineup)
+}
+
+// buildLineup valida a escalação e monta os registros a serem gravados
+func (c *MatchLineupController) buildLineup(match model.Match, input model.LineupSubmit) ([]model.MatchLineupPlayer, error) {
+	slots, err := formationSlots(input.Formation)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(input.Starters)+len(input.Bench))
+	for _, p := range append(append([]model.LineupPlayerInput{}, input.Starters...), input.Bench...) {
+		ids = append(ids, p.PlayerID)
+	}
+
+	var players []model.Player
+	if err := c.DB.Where("id IN ?", ids).Find(&players).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]model.Player, len(players))
+	for _, p := range players {
+		byID[p.ID] = p
+	}
+
+	// O vínculo considerado é o da data da partida, não o time atual
+	teams, err := playerTeamsAt(c.DB, players, match.StartTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Suspensões de outros torneios não impedem a escalação
+	suspended, err := suspendedInTournament(c.DB, match.TournamentID, players)
+	if err != nil {
+		return nil, err
+	}
+
+	seenPlayers := make(map[uint]bool)
+	seenNumbers := make(map[int]bool)
+	positions := make(map[string]int)
+	captains := 0
+	var rows []model.MatchLineupPlayer
+
+	add := func(entry model.LineupPlayerInput, role string) error {
+		player, ok := byID[entry.PlayerID]
+		if !ok {
+			return fmt.Errorf("jogador %d não encontrado", entry.PlayerID)
+		}
+		if teams[player.ID] != input.TeamID {
+			return fmt.Errorf("o jogador %s não pertencia ao time na data da partida", player.Name)
+		}
+		if suspended[player.ID] {
+			return fmt.Errorf("o jogador %s está suspenso", player.Name)
+		}
+		switch player.Status {
+		case "injured":
+			return fmt.Errorf("o jogador %s está lesionado", player.Name)
+		case "inactive":
+			return fmt.Errorf("o jogador %s está inativo", player.Name)
+		}
+		if seenPlayers[player.ID] {
+			return fmt.Errorf("o jogador %s foi relacionado mais de uma vez", player.Name)
+		}
+		if seenNumbers[entry.ShirtNumber] {
+			return fmt.Errorf("a camisa %d foi usada mais de uma vez", entry.ShirtNumber)
+		}
+		seenPlayers[player.ID] = true
+		seenNumbers[entry.ShirtNumber] = true
+
+		position := entry.Position
+		if position == "" {
+			position = player.Position
+		}
+		if role == model.LineupRoleStarter {
+			positions[position]++
+		}
+		if entry.IsCaptain {
+			captains++
+		}
+
+		rows = append(rows, model.MatchLineupPlayer{
+			MatchID:     match.ID,
+			TeamID:      input.TeamID,
+			PlayerID:    player.ID,
+			ShirtNumber: entry.ShirtNumber,
+			Role:        role,
+			Position:    position,
+			IsCaptain:   entry.IsCaptain,
+		})
+		return nil
+	}
+
+	for _, entry := range input.Starters {
+		if err := add(entry, model.LineupRoleStarter); err != nil {
+			return nil, err
+		}
+	}
+	for _, entry := range input.Bench {
+		if err := add(entry, model.LineupRoleBench); err != nil {
+			return nil, err
+		}
+	}
+
+	if positions[model.PositionGoalkeeper] != 1 {
+		return nil, errors.New("a escalação titular deve ter exatamente um goleiro")
+	}
+	for _, position := range []string{model.PositionDefender, model.PositionMidfielder, model.PositionForward} {
+		if positions[position] != slots[position] {
+			return nil, fmt.Errorf("o esquema %s exige %d jogadores na posição %s, mas foram escalados %d",
+				input.Formation, slots[position], position, positions[position])
+		}
+	}
+	if captains > 1 {
+		return nil, errors.New("apenas um jogador pode ser o capitão")
+	}
+
+	return rows, nil
+}
+
+// loadLineup monta a escalação de um time. O segundo retorno é falso quando o
+// time ainda não tem escalação cadastrada.
+func loadLineup(db *gorm.DB, match model.Match, teamID uint) (model.LineupResponse, bool, error) {
+	lineup := model.LineupResponse{
+		MatchID:  match.ID,
+		TeamID:   teamID,
+		IsHome:   teamID == match.HomeTeamID,
+		Starters: []model.LineupPlayerResponse{},
+		Bench:    []model.LineupPlayerResponse{},
+	}
+
+	var rows []model.MatchLineupPlayer
+	if err := db.Where("match_id = ? AND team_id = ?", match.ID, teamID).Order("role DESC, shirt_number").
+		Find(&rows).Error; err != nil {
+		return lineup, false, err
+	}
+	if len(rows) == 0 {
+		return lineup, false, nil
+	}
+
+	var matchTeam model.MatchTeam
+	if err := db.Where("match_id = ? AND team_id = ?", match.ID, teamID).First(&matchTeam).Error; err == nil {
+		lineup.Formation = matchTeam.Formation
+	}
+
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.PlayerID)
+	}
+	var players []model.Player
+	if err := db.Where("id IN ?", ids).Find(&players).Error; err != nil {
+		return lineup, false, err
+	}
+	names := make(map[uint]string, len(players))
+	for _, p := range players {
+		names[p.ID] = p.Name
+	}
+
+	for _, row := range rows {
+		item := model.LineupPlayerResponse{
+			PlayerID:        row.PlayerID,
+			Name:            names[row.PlayerID],
+			ShirtNumber:     row.ShirtNumber,
+			Position:        row.Position,
+			Role:            row.Role,
+			IsCaptain:       row.IsCaptain,
+			SubbedInMinute:  row.SubbedInMinute,
+			SubbedOutMinute: row.SubbedOutMinute,
+		}
+		if row.Role == model.LineupRoleStarter {
+			lineup.Starters = append(lineup.Starters, item)
+		} else {
+			lineup.Bench = append(lineup.Bench, item)
+		}
+	}
+
+	return lineup, true, nil
+}
+
+// validateSubstitution confere uma substituição contra a escalação do time:
+// quem sai precisa estar em campo, quem entra precisa ser um reserva que
+// ainda não entrou, e o limite de substituições não pode ser ultrapassado.
+func validateSubstitution(db *gorm.DB, match model.Match, event model.MatchEventCreate) error {
+	if event.TeamID != match.HomeTeamID && event.TeamID != match.AwayTeamID {
+		return errors.New("o time não participa desta partida")
+	}
+
+	var rows []model.MatchLineupPlayer
+	if err := db.Where("match_id = ? AND team_id = ?", match.ID, event.TeamID).Find(&rows).Error; err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return errors.New("o time não tem escalação cadastrada para esta partida")
+	}
+
+	var out, in *model.MatchLineupPlayer
+	substitutions := 0
+	for i := range rows {
+		row := &rows[i]
+		if row.PlayerID == event.SubOutPlayerID {
+			out = row
+		}
+		if row.PlayerID == event.SubInPlayerID {
+			in = row
+		}
+		if row.SubbedInMinute != nil {
+			substitutions++
+		}
+	}
+
+	if event.SubInPlayerID == event.SubOutPlayerID {
+		return errors.New("o jogador que entra deve ser diferente do que sai")
+	}
+	if out == nil {
+		return errors.New("o jogador que sai não está na escalação")
+	}
+	onPitch := (out.Role == model.LineupRoleStarter || out.SubbedInMinute != nil) && out.SubbedOutMinute == nil
+	if !onPitch {
+		return errors.New("o jogador que sai não está em campo")
+	}
+	if in == nil || in.Role != model.LineupRoleBench {
+		return errors.New("o jogador que entra não está entre os reservas")
+	}
+	if in.SubbedInMinute != nil {
+		return errors.New("o jogador que entra já foi utilizado")
+	}
+	if out.SubbedInMinute != nil && *out.SubbedInMinute > event.Minute {
+		return errors.New("o jogador que sai entrou depois do minuto informado")
+	}
+	if substitutions >= maxSubstitutions {
+		return fmt.Errorf("o time já fez as %d substituições permitidas", maxSubstitutions)
+	}
+	return nil
+}
+
+// applySubstitution registra na escalação os minutos de entrada e saída
+func applySubstitution(tx *gorm.DB, event model.MatchEvent) error {
+	if err := tx.Model(&model.MatchLineupPlayer{}).
+		Where("match_id = ? AND team_id = ? AND player_id = ?", event.MatchID, event.TeamID, event.SubOutPlayerID).
+		Update("subbed_out_minute", event.Minute).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.MatchLineupPlayer{}).
+		Where("match_id = ? AND team_id = ? AND player_id = ?", event.MatchID, event.TeamID, event.SubInPlayerID).
+		Update("subbed_in_minute", event.Minute).Error
+}
+
+// revertSubstitution desfaz na escalação uma substituição excluída
+func revertSubstitution(tx *gorm.DB, event model.MatchEvent) error {
+	if err := tx.Model(&model.MatchLineupPlayer{}).
+		Where("match_id = ? AND team_id = ? AND player_id = ?", event.MatchID, event.TeamID, event.SubOutPlayerID).
+		Update("subbed_out_minute", nil).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.MatchLineupPlayer{}).
+		Where("match_id = ? AND team_id = ? AND player_id = ?", event.MatchID, event.TeamID, event.SubInPlayerID).
+		Update("subbed_in_minute", nil).Error
+}
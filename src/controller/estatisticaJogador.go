@@ -0,0 +1,312 @@
+package controller
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/config"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"gorm.io/gorm"
+)
+
+// Duração de uma partida para o cálculo de minutos jogados
+const (
+	regularTimeMinutes = 90
+	extraTimeMinutes   = 30
+)
+
+// defaultLeaderboardLimit é a quantidade padrão de jogadores nos rankings
+const defaultLeaderboardLimit = 20
+
+// matchStatsFilter restringe as consultas às partidas encerradas que atendem
+// aos filtros de torneio e período
+type matchStatsFilter struct {
+	TournamentID *uint
+	StartDate    *time.Time
+	EndDate      *time.Time
+}
+
+// apply aplica o filtro a uma consulta que faz JOIN com a tabela matches
+func (f matchStatsFilter) apply(query *gorm.DB) *gorm.DB {
+	query = query.Where("matches.status = ?", model.MatchStatusFinished)
+	if f.TournamentID != nil {
+		query = query.Where("matches.tournament_id = ?", *f.TournamentID)
+	}
+	if f.StartDate != nil {
+		query = query.Where("matches.start_time >= ?", *f.StartDate)
+	}
+	if f.EndDate != nil {
+		// A data final é inclusiva
+		query = query.Where("matches.start_time < ?", f.EndDate.AddDate(0, 0, 1))
+	}
+	return query
+}
+
+// matchMinutes retorna a duração da partida, considerando a prorrogação
+func matchMinutes(match model.Match) int {
+	if match.HomeExtraTimeScore != nil && match.AwayExtraTimeScore != nil {
+		return regularTimeMinutes + extraTimeMinutes
+	}
+	return regularTimeMinutes
+}
+
+// GetPlayerStats retorna as estatísticas de um jogador, com filtros opcionais
+// de torneio (tournament_id) e período (start_date e end_date, AAAA-MM-DD)
+func GetPlayerStats(c *gin.Context) {
+	var player model.Player
+	if err := config.DB.First(&player, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Jogador não encontrado"})
+		return
+	}
+
+	var filter matchStatsFilter
+	stats := model.PlayerStatsResponse{
+		PlayerID:    player.ID,
+		Name:        player.Name,
+		TeamID:      player.TeamID,
+		GoalsByType: map[string]int{},
+		Cards:       map[string]int{},
+	}
+	if value := c.Query("tournament_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "tournament_id inválido"})
+			return
+		}
+		tournamentID := uint(id)
+		filter.TournamentID = &tournamentID
+		stats.TournamentID = &tournamentID
+	}
+	if value := c.Query("start_date"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data inicial inválida, use o formato AAAA-MM-DD"})
+			return
+		}
+		filter.StartDate = &date
+		stats.StartDate = value
+	}
+	if value := c.Query("end_date"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data final inválida, use o formato AAAA-MM-DD"})
+			return
+		}
+		filter.EndDate = &date
+		stats.EndDate = value
+	}
+
+	// Eventos do próprio jogador (gols e cartões)
+	var events []model.MatchEvent
+	if err := filter.apply(config.DB.Model(&model.MatchEvent{}).
+		Joins("JOIN matches ON matches.id = match_events.match_id").
+		Where("match_events.player_id = ?", player.ID)).
+		Select("match_events.*").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar eventos do jogador"})
+		return
+	}
+
+	// Participações registradas nas escalações
+	var lineups []model.MatchLineupPlayer
+	if err := filter.apply(config.DB.Model(&model.MatchLineupPlayer{}).
+		Joins("JOIN matches ON matches.id = match_lineup_players.match_id").
+		Where("match_lineup_players.player_id = ?", player.ID)).
+		Select("match_lineup_players.*").Find(&lineups).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar escalações do jogador"})
+		return
+	}
+
+	matchIDs := make(map[uint]bool)
+	for _, e := range events {
+		matchIDs[e.MatchID] = true
+	}
+	for _, l := range lineups {
+		matchIDs[l.MatchID] = true
+	}
+	ids := make([]uint, 0, len(matchIDs))
+	for id := range matchIDs {
+		ids = append(ids, id)
+	}
+	matches := make(map[uint]model.Match, len(ids))
+	if len(ids) > 0 {
+		var rows []model.Match
+		if err := config.DB.Where("id IN ?", ids).Find(&rows).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar partidas do jogador"})
+			return
+		}
+		for _, m := range rows {
+			matches[m.ID] = m
+		}
+	}
+
+	// Uma expulsão, por vermelho ou pelo segundo amarelo na mesma partida,
+	// encerra os minutos do jogador na partida
+	sentOff := make(map[uint]int)
+	dismiss := func(matchID uint, minute int) {
+		if current, ok := sentOff[matchID]; !ok || minute < current {
+			sentOff[matchID] = minute
+		}
+	}
+	yellows := make(map[uint][]int)
+	for _, e := range events {
+		switch e.EventType {
+		case model.EventTypeGoal:
+			// Cobranças da disputa de pênaltis não contam como gols
+			if e.Period == model.PeriodPenalties {
+				continue
+			}
+			stats.GoalsByType[e.GoalType]++
+			if e.GoalType != model.GoalTypeOwnGoal {
+				stats.Goals++
+			}
+		case model.EventTypeCard:
+			stats.Cards[e.CardType]++
+			switch e.CardType {
+			case model.CardTypeRed:
+				dismiss(e.MatchID, e.Minute)
+			case model.CardTypeYellow:
+				yellows[e.MatchID] = append(yellows[e.MatchID], e.Minute)
+			}
+		}
+	}
+	for matchID, minutes := range yellows {
+		if len(minutes) >= 2 {
+			sort.Ints(minutes)
+			dismiss(matchID, minutes[1])
+		}
+	}
+
+	inLineup := make(map[uint]bool, len(lineups))
+	for _, l := range lineups {
+		inLineup[l.MatchID] = true
+		played := l.Role == model.LineupRoleStarter || l.SubbedInMinute != nil
+		if !played {
+			continue
+		}
+
+		stats.Appearances++
+		start := 0
+		if l.Role == model.LineupRoleStarter {
+			stats.Starts++
+		} else {
+			stats.SubstituteAppearances++
+			stats.SubbedIn++
+			start = *l.SubbedInMinute
+		}
+
+		end := matchMinutes(matches[l.MatchID])
+		if l.SubbedOutMinute != nil {
+			stats.SubbedOut++
+			end = *l.SubbedOutMinute
+		}
+		if minute, ok := sentOff[l.MatchID]; ok && minute < end {
+			end = minute
+		}
+		if end > start {
+			stats.MinutesPlayed += end - start
+		}
+	}
+
+	// Partidas sem escalação cadastrada contam como participação quando há eventos do jogador
+	for id := range matchIDs {
+		if !inLineup[id] {
+			stats.Appearances++
+		}
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// leaderboardLimit lê o parâmetro limit dos rankings
+func leaderboardLimit(ctx *gin.Context) int {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(defaultLeaderboardLimit)))
+	if err != nil || limit < 1 || limit > 100 {
+		return defaultLeaderboardLimit
+	}
+	return limit
+}
+
+// GetTopScorers retorna a artilharia do torneio. Gols contra não são contados.
+func (c *TournamentController) GetTopScorers(ctx *gin.Context) {
+	var tournament model.Tournament
+	if err := c.DB.First(&tournament, ctx.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Torneio não encontrado"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar torneio"})
+		return
+	}
+
+	rows := []model.TopScorerRow{}
+	filter := matchStatsFilter{TournamentID: &tournament.ID}
+	if err := filter.apply(c.DB.Table("match_events").
+		Select(`match_events.player_id, players.name AS player_name, match_events.team_id,
+			COUNT(*) AS goals,
+			SUM(CASE WHEN match_events.goal_type = ? THEN 1 ELSE 0 END) AS penalties`, model.GoalTypePenalty).
+		Joins("JOIN matches ON matches.id = match_events.match_id").
+		Joins("JOIN players ON players.id = match_events.player_id").
+		Where("match_events.event_type = ? AND match_events.goal_type <> ? AND match_events.period <> ?",
+			model.EventTypeGoal, model.GoalTypeOwnGoal, model.PeriodPenalties)).
+		Group("match_events.player_id, players.name, match_events.team_id").
+		Order("goals DESC, penalties ASC, players.name").
+		Limit(leaderboardLimit(ctx)).
+		Scan(&rows).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular artilharia", "details": err.Error()})
+		return
+	}
+
+	// Jogadores com a mesma quantidade de gols dividem a posição
+	for i := range rows {
+		rows[i].Position = i + 1
+		if i > 0 && rows[i].Goals == rows[i-1].Goals {
+			rows[i].Position = rows[i-1].Position
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"tournament_id": tournament.ID, "data": rows})
+}
+
+// GetDiscipline retorna o ranking disciplinar do torneio
+func (c *TournamentController) GetDiscipline(ctx *gin.Context) {
+	var tournament model.Tournament
+	if err := c.DB.First(&tournament, ctx.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Torneio não encontrado"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar torneio"})
+		return
+	}
+
+	rows := []model.DisciplineRow{}
+	filter := matchStatsFilter{TournamentID: &tournament.ID}
+	if err := filter.apply(c.DB.Table("match_events").
+		Select(`match_events.player_id, players.name AS player_name, match_events.team_id,
+			SUM(CASE WHEN match_events.card_type = ? THEN 1 ELSE 0 END) AS yellow_cards,
+			SUM(CASE WHEN match_events.card_type = ? THEN 1 ELSE 0 END) AS red_cards,
+			SUM(CASE WHEN match_events.card_type = ? THEN 3 ELSE 1 END) AS points`,
+			model.CardTypeYellow, model.CardTypeRed, model.CardTypeRed).
+		Joins("JOIN matches ON matches.id = match_events.match_id").
+		Joins("JOIN players ON players.id = match_events.player_id").
+		Where("match_events.event_type = ?", model.EventTypeCard)).
+		Group("match_events.player_id, players.name, match_events.team_id").
+		Order("points DESC, red_cards DESC, players.name").
+		Limit(leaderboardLimit(ctx)).
+		Scan(&rows).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular ranking disciplinar", "details": err.Error()})
+		return
+	}
+
+	for i := range rows {
+		rows[i].Position = i + 1
+		if i > 0 && rows[i].Points == rows[i-1].Points && rows[i].RedCards == rows[i-1].RedCards {
+			rows[i].Position = rows[i-1].Position
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"tournament_id": tournament.ID, "data": rows})
+}
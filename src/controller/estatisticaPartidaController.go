@@ -0,0 +1,319 @@
+package controller
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+)
+
+type MatchStatisticsController struct {
+	DB *gorm.DB
+}
+
+func NewMatchStatisticsController(db *gorm.DB) *MatchStatisticsController {
+	return &MatchStatisticsController{DB: db}
+}
+
+// GetStatistics retorna as estatísticas de uma partida
+func (c *MatchStatisticsController) GetStatistics(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var match model.Match
+	if err := c.DB.First(&match, id).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Partida não encontrada"})
+		return
+	}
+
+	var stats model.MatchStatistics
+	if err := c.DB.Where("match_id = ?", match.ID).First(&stats).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Estatísticas não encontradas para esta partida"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar estatísticas"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toMatchStatisticsResponse(stats))
+}
+
+// UpsertStatistics cria ou substitui as estatísticas de uma partida
+func (c *MatchStatisticsController) UpsertStatistics(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var match model.Match
+	if err := c.DB.First(&match, id).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Partida não encontrada"})
+		return
+	}
+
+	var input model.MatchStatisticsCreate
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	// A partida da URL prevalece sobre a do corpo
+	if input.MatchID != 0 && input.MatchID != match.ID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "A partida informada no corpo não corresponde à partida da URL"})
+		return
+	}
+	input.MatchID = match.ID
+
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	var stats model.MatchStatistics
+	err := c.DB.Where("match_id = ?", match.ID).First(&stats).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar estatísticas"})
+		return
+	}
+	created := err == gorm.ErrRecordNotFound
+
+	stats.MatchID = input.MatchID
+	stats.HomeTeamID = input.HomeTeamID
+	stats.AwayTeamID = input.AwayTeamID
+	stats.HomePossession = input.HomePossession
+	stats.AwayPossession = input.AwayPossession
+	stats.HomeShots = input.HomeShots
+	stats.AwayShots = input.AwayShots
+	stats.HomeShotsOnTarget = input.HomeShotsOnTarget
+	stats.AwayShotsOnTarget = input.AwayShotsOnTarget
+	stats.HomeCorners = input.HomeCorners
+	stats.AwayCorners = input.AwayCorners
+	stats.HomeFouls = input.HomeFouls
+	stats.AwayFouls = input.AwayFouls
+	stats.HomeYellowCards = input.HomeYellowCards
+	stats.AwayYellowCards = input.AwayYellowCards
+	stats.HomeRedCards = input.HomeRedCards
+	stats.AwayRedCards = input.AwayRedCards
+	stats.HomeOffsides = input.HomeOffsides
+	stats.AwayOffsides = input.AwayOffsides
+	stats.HomePasses = input.HomePasses
+	stats.AwayPasses = input.AwayPasses
+	stats.HomePassAccuracy = input.HomePassAccuracy
+	stats.AwayPassAccuracy = input.AwayPassAccuracy
+	stats.HomeSaves = input.HomeSaves
+	stats.AwaySaves = input.AwaySaves
+
+	if err := validateMatchStatistics(stats, match); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.saveStatistics(&stats, match); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar estatísticas", "details": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	ctx.JSON(status, toMatchStatisticsResponse(stats))
+}
+
+// UpdateStatistics atualiza parcialmente as estatísticas de uma partida
+func (c *MatchStatisticsController) UpdateStatistics(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var match model.Match
+	if err := c.DB.First(&match, id).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Partida não encontrada"})
+		return
+	}
+
+	var stats model.MatchStatistics
+	if err := c.DB.Where("match_id = ?", match.ID).First(&stats).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Estatísticas não encontradas para esta partida"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar estatísticas"})
+		return
+	}
+
+	var updateData model.MatchStatisticsUpdate
+	if err := ctx.ShouldBindJSON(&updateData); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if err := util.ValidateStruct(updateData); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	// Atualizar apenas os campos fornecidos
+	if updateData.HomePossession != nil {
+		stats.HomePossession = *updateData.HomePossession
+	}
+	if updateData.AwayPossession != nil {
+		stats.AwayPossession = *updateData.AwayPossession
+	}
+	if updateData.HomeShots != nil {
+		stats.HomeShots = *updateData.HomeShots
+	}
+	if updateData.AwayShots != nil {
+		stats.AwayShots = *updateData.AwayShots
+	}
+	if updateData.HomeShotsOnTarget != nil {
+		stats.HomeShotsOnTarget = *updateData.HomeShotsOnTarget
+	}
+	if updateData.AwayShotsOnTarget != nil {
+		stats.AwayShotsOnTarget = *updateData.AwayShotsOnTarget
+	}
+	if updateData.HomeCorners != nil {
+		stats.HomeCorners = *updateData.HomeCorners
+	}
+	if updateData.AwayCorners != nil {
+		stats.AwayCorners = *updateData.AwayCorners
+	}
+	if updateData.HomeFouls != nil {
+		stats.HomeFouls = *updateData.HomeFouls
+	}
+	if updateData.AwayFouls != nil {
+		stats.AwayFouls = *updateData.AwayFouls
+	}
+	if updateData.HomeYellowCards != nil {
+		stats.HomeYellowCards = *updateData.HomeYellowCards
+	}
+	if updateData.AwayYellowCards != nil {
+		stats.AwayYellowCards = *updateData.AwayYellowCards
+	}
+	if updateData.HomeRedCards != nil {
+		stats.HomeRedCards = *updateData.HomeRedCards
+	}
+	if updateData.AwayRedCards != nil {
+		stats.AwayRedCards = *updateData.AwayRedCards
+	}
+	if updateData.HomeOffsides != nil {
+		stats.HomeOffsides = *updateData.HomeOffsides
+	}
+	if updateData.AwayOffsides != nil {
+		stats.AwayOffsides = *updateData.AwayOffsides
+	}
+	if updateData.HomePasses != nil {
+		stats.HomePasses = *updateData.HomePasses
+	}
+	if updateData.AwayPasses != nil {
+		stats.AwayPasses = *updateData.AwayPasses
+	}
+	if updateData.HomePassAccuracy != nil {
+		stats.HomePassAccuracy = *updateData.HomePassAccuracy
+	}
+	if updateData.AwayPassAccuracy != nil {
+		stats.AwayPassAccuracy = *updateData.AwayPassAccuracy
+	}
+	if updateData.HomeSaves != nil {
+		stats.HomeSaves = *updateData.HomeSaves
+	}
+	if updateData.AwaySaves != nil {
+		stats.AwaySaves = *updateData.AwaySaves
+	}
+
+	if err := validateMatchStatistics(stats, match); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.saveStatistics(&stats, match); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar estatísticas", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toMatchStatisticsResponse(stats))
+}
+
+// saveStatistics grava as estatísticas. Em partidas encerradas, liquida as
+// apostas de escanteios e cartões que aguardavam as estatísticas e reliquida
+// as já liquidadas, caso os números tenham sido corrigidos.
+func (c *MatchStatisticsController) saveStatistics(stats *model.MatchStatistics, match model.Match) error {
+	err := c.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockMatch(tx, &match); err != nil {
+			return err
+		}
+		if err := tx.Save(stats).Error; err != nil {
+			return err
+		}
+		if match.Status != model.MatchStatusFinished {
+			return nil
+		}
+		if _, err := resettleMatchBets(tx, match, "estatísticas da partida corrigidas"); err != nil {
+			return err
+		}
+		_, err := settleMatchBets(tx, match)
+		return err
+	})
+	if err == nil && match.Status == model.MatchStatusFinished {
+		invalidateMatchAnalytics(c.DB, match.ID)
+	}
+	return err
+}
+
+// validateMatchStatistics verifica as regras que envolvem mais de um campo
+func validateMatchStatistics(stats model.MatchStatistics, match model.Match) error {
+	if stats.HomeTeamID != match.HomeTeamID {
+		return fmt.Errorf("time da casa não corresponde ao time da casa da partida")
+	}
+	if stats.AwayTeamID != match.AwayTeamID {
+		return fmt.Errorf("time visitante não corresponde ao time visitante da partida")
+	}
+	if math.Abs(stats.HomePossession+stats.AwayPossession-100) > 0.01 {
+		return fmt.Errorf("a soma da posse de bola dos dois times deve ser 100")
+	}
+	if stats.HomeShotsOnTarget > stats.HomeShots {
+		return fmt.Errorf("chutes no gol do time da casa não podem exceder o total de chutes")
+	}
+	if stats.AwayShotsOnTarget > stats.AwayShots {
+		return fmt.Errorf("chutes no gol do time visitante não podem exceder o total de chutes")
+	}
+	if stats.HomeSaves > stats.AwayShotsOnTarget {
+		return fmt.Errorf("defesas do time da casa não podem exceder os chutes no gol do visitante")
+	}
+	if stats.AwaySaves > stats.HomeShotsOnTarget {
+		return fmt.Errorf("defesas do time visitante não podem exceder os chutes no gol do time da casa")
+	}
+	return nil
+}
+
+func toMatchStatisticsResponse(stats model.MatchStatistics) model.MatchStatisticsResponse {
+	return model.MatchStatisticsResponse{
+		ID:                stats.ID,
+		MatchID:           stats.MatchID,
+		HomeTeamID:        stats.HomeTeamID,
+		AwayTeamID:        stats.AwayTeamID,
+		HomePossession:    stats.HomePossession,
+		AwayPossession:    stats.AwayPossession,
+		HomeShots:         stats.HomeShots,
+		AwayShots:         stats.AwayShots,
+		HomeShotsOnTarget: stats.HomeShotsOnTarget,
+		AwayShotsOnTarget: stats.AwayShotsOnTarget,
+		HomeCorners:       stats.HomeCorners,
+		AwayCorners:       stats.AwayCorners,
+		HomeFouls:         stats.HomeFouls,
+		AwayFouls:         stats.AwayFouls,
+		HomeYellowCards:   stats.HomeYellowCards,
+		AwayYellowCards:   stats.AwayYellowCards,
+		HomeRedCards:      stats.HomeRedCards,
+		AwayRedCards:      stats.AwayRedCards,
+		HomeOffsides:      stats.HomeOffsides,
+		AwayOffsides:      stats.AwayOffsides,
+		HomePasses:        stats.HomePasses,
+		AwayPasses:        stats.AwayPasses,
+		HomePassAccuracy:  stats.HomePassAccuracy,
+		AwayPassAccuracy:  stats.AwayPassAccuracy,
+		HomeSaves:         stats.HomeSaves,
+		AwaySaves:         stats.AwaySaves,
+		CreatedAt:         stats.CreatedAt,
+		UpdatedAt:         stats.UpdatedAt,
+	}
+}
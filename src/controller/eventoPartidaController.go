@@ -6,15 +6,17 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
 	"gorm.io/gorm"
 )
 
 type MatchEventController struct {
-	DB *gorm.DB
+	DB    *gorm.DB
+	Cache util.Cache
 }
 
-func NewMatchEventController(db *gorm.DB) *MatchEventController {
-	return &MatchEventController{DB: db}
+func NewMatchEventController(db *gorm.DB, cache util.Cache) *MatchEventController {
+	return &MatchEventController{DB: db, Cache: cache}
 }
 
 func (c *MatchEventController) CreateEvent(ctx *gin.Context) {
@@ -45,18 +47,39 @@ func (c *MatchEventController) CreateEvent(ctx *gin.Context) {
 		return
 	}
 
+	// O jogador precisa pertencer ao time do evento na data da partida
+	if ok := c.checkPlayerTeam(ctx, match, player, event.TeamID); !ok {
+		return
+	}
+
 	// Validar campos específicos do tipo de evento
 	if err := c.validateEventFields(event); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	period, err := normalizeEventTiming(event.Period, event.Minute, event.AddedMinute)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Substituições precisam respeitar a escalação do time
+	if event.EventType == model.EventTypeSubstitution {
+		if err := validateSubstitution(c.DB, match, event); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	newEvent := model.MatchEvent{
 		MatchID:        event.MatchID,
 		EventType:      event.EventType,
 		TeamID:         event.TeamID,
 		PlayerID:       event.PlayerID,
 		Minute:         event.Minute,
+		Period:         period,
+		AddedMinute:    event.AddedMinute,
 		Description:    event.Description,
 		GoalType:       event.GoalType,
 		CardType:       event.CardType,
@@ -67,10 +90,45 @@ func (c *MatchEventController) CreateEvent(ctx *gin.Context) {
 		CornerSide:     event.CornerSide,
 	}
 
-	if err := c.DB.Create(&newEvent).Error; err != nil {
+	scoreChanged := false
+	resettledBets := 0
+	err = c.DB.Transaction(func(tx *gorm.DB) error {
+		// A partida é relida com bloqueio: a correção do placar parte do valor atual
+		if err := lockMatch(tx, &match); err != nil {
+			return err
+		}
+		if err := tx.Create(&newEvent).Error; err != nil {
+			return err
+		}
+		switch newEvent.EventType {
+		case model.EventTypeSubstitution:
+			if err := applySubstitution(tx, newEvent); err != nil {
+				return err
+			}
+		case model.EventTypeCard:
+			if _, err := applyCardSuspension(tx, match, newEvent); err != nil {
+				return err
+			}
+		}
+
+		// Um gol registrado depois do encerramento corrige o placar e as apostas
+		resettled, changed, err := applyEventCorrection(tx, &match, nil, &newEvent, "Evento registrado após o encerramento")
+		if err != nil {
+			return err
+		}
+		scoreChanged, resettledBets = changed, resettled
+		return recordEventRevision(tx, model.RevisionActionCreated, nil, &newEvent, event.Reason, ctx.GetUint("user_id"), resettled)
+	})
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar evento"})
 		return
 	}
+	if scoreChanged {
+		invalidateStandings(c.Cache, match.TournamentID)
+	}
+	if resettledBets > 0 {
+		invalidateMatchAnalytics(c.DB, match.ID)
+	}
 
 	ctx.JSON(http.StatusCreated, newEvent)
 }
@@ -138,6 +196,29 @@ func (c *MatchEventController) UpdateEvent(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
 		return
 	}
+	if err := util.ValidateStruct(updateData); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	before := event
+
+	// Os jogadores de uma substituição só mudam excluindo e registrando o evento novamente
+	isSubstitution := event.EventType == model.EventTypeSubstitution
+	if (updateData.EventType != "" && (updateData.EventType == model.EventTypeSubstitution) != isSubstitution) ||
+		(isSubstitution && (updateData.TeamID != 0 || updateData.SubInPlayerID != 0 || updateData.SubOutPlayerID != 0)) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Substituições não podem ser alteradas; exclua o evento e registre novamente"})
+		return
+	}
+
+	// Cartões podem ter gerado suspensões; jogador e tipo só mudam excluindo o evento
+	isCard := event.EventType == model.EventTypeCard
+	if (updateData.EventType != "" && (updateData.EventType == model.EventTypeCard) != isCard) ||
+		(isCard && ((updateData.CardType != "" && updateData.CardType != event.CardType) ||
+			(updateData.PlayerID != 0 && updateData.PlayerID != event.PlayerID) ||
+			(updateData.TeamID != 0 && updateData.TeamID != event.TeamID))) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Cartões não podem ter jogador ou tipo alterados; exclua o evento e registre novamente"})
+		return
+	}
 
 	// Atualizar campos
 	if updateData.EventType != "" {
@@ -149,8 +230,20 @@ func (c *MatchEventController) UpdateEvent(ctx *gin.Context) {
 	if updateData.PlayerID != 0 {
 		event.PlayerID = updateData.PlayerID
 	}
-	if updateData.Minute != 0 {
-		event.Minute = updateData.Minute
+	if updateData.Minute != 0 || updateData.Period != "" || updateData.AddedMinute != nil {
+		if updateData.Minute != 0 {
+			event.Minute = updateData.Minute
+		}
+		if updateData.AddedMinute != nil {
+			event.AddedMinute = *updateData.AddedMinute
+		}
+		// Ao mudar só o minuto, o período volta a ser deduzido
+		period, err := normalizeEventTiming(updateData.Period, event.Minute, event.AddedMinute)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		event.Period = period
 	}
 	if updateData.Description != "" {
 		event.Description = updateData.Description
@@ -177,10 +270,56 @@ func (c *MatchEventController) UpdateEvent(ctx *gin.Context) {
 		event.CornerSide = updateData.CornerSide
 	}
 
-	if err := c.DB.Save(&event).Error; err != nil {
+	var match model.Match
+	if err := c.DB.First(&match, event.MatchID).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar partida"})
+		return
+	}
+	if updateData.PlayerID != 0 || updateData.TeamID != 0 {
+		var player model.Player
+		if err := c.DB.First(&player, event.PlayerID).Error; err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Jogador não encontrado"})
+			return
+		}
+		if ok := c.checkPlayerTeam(ctx, match, player, event.TeamID); !ok {
+			return
+		}
+	}
+
+	scoreChanged := false
+	resettledBets := 0
+	err := c.DB.Transaction(func(tx *gorm.DB) error {
+		// A partida é relida com bloqueio: a correção do placar parte do valor atual
+		if err := lockMatch(tx, &match); err != nil {
+			return err
+		}
+		if err := tx.Save(&event).Error; err != nil {
+			return err
+		}
+		// Mantém os minutos de entrada e saída da escalação
+		if isSubstitution && updateData.Minute != 0 {
+			if err := applySubstitution(tx, event); err != nil {
+				return err
+			}
+		}
+
+		resettled, changed, err := applyEventCorrection(tx, &match, &before, &event, updateData.Reason)
+		if err != nil {
+			return err
+		}
+		scoreChanged, resettledBets = changed, resettled
+		return recordEventRevision(tx, model.RevisionActionUpdated, &before, &event, updateData.Reason, ctx.GetUint("user_id"), resettled)
+	})
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar evento"})
 		return
 	}
+	if scoreChanged {
+		invalidateStandings(c.Cache, match.TournamentID)
+	}
+	if resettledBets > 0 {
+		invalidateMatchAnalytics(c.DB, match.ID)
+	}
 
 	ctx.JSON(http.StatusOK, event)
 }
@@ -198,14 +337,92 @@ func (c *MatchEventController) DeleteEvent(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.DB.Delete(&event).Error; err != nil {
+	// Toda exclusão precisa de justificativa, registrada no histórico do evento
+	reason := ctx.Query("reason")
+	if len(reason) < 3 || len(reason) > 500 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Informe o motivo da exclusão no parâmetro reason (3 a 500 caracteres)"})
+		return
+	}
+
+	var match model.Match
+	if err := c.DB.First(&match, event.MatchID).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar partida"})
+		return
+	}
+
+	if event.EventType == model.EventTypeSubstitution {
+		// Quem entrou e depois foi substituído depende deste evento
+		var laterOut int64
+		if err := c.DB.Model(&model.MatchLineupPlayer{}).
+			Where("match_id = ? AND team_id = ? AND player_id = ? AND subbed_out_minute IS NOT NULL",
+				event.MatchID, event.TeamID, event.SubInPlayerID).
+			Count(&laterOut).Error; err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar escalação"})
+			return
+		}
+		if laterOut > 0 {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Exclua primeiro a substituição posterior do jogador que entrou"})
+			return
+		}
+	}
+
+	scoreChanged := false
+	resettledBets := 0
+	err := c.DB.Transaction(func(tx *gorm.DB) error {
+		// A partida é relida com bloqueio: a correção do placar parte do valor atual
+		if err := lockMatch(tx, &match); err != nil {
+			return err
+		}
+		if err := tx.Delete(&event).Error; err != nil {
+			return err
+		}
+		switch event.EventType {
+		case model.EventTypeSubstitution:
+			if err := revertSubstitution(tx, event); err != nil {
+				return err
+			}
+		case model.EventTypeCard:
+			if err := cancelCardSuspension(tx, match, event); err != nil {
+				return err
+			}
+		}
+
+		resettled, changed, err := applyEventCorrection(tx, &match, &event, nil, reason)
+		if err != nil {
+			return err
+		}
+		scoreChanged, resettledBets = changed, resettled
+		return recordEventRevision(tx, model.RevisionActionDeleted, &event, nil, reason, ctx.GetUint("user_id"), resettled)
+	})
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir evento"})
 		return
 	}
+	if scoreChanged {
+		invalidateStandings(c.Cache, match.TournamentID)
+	}
+	if resettledBets > 0 {
+		invalidateMatchAnalytics(c.DB, match.ID)
+	}
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "Evento excluído com sucesso"})
 }
 
+// checkPlayerTeam verifica, pelo histórico de transferências, se o jogador
+// pertencia ao time na data da partida e responde com erro caso contrário
+func (c *MatchEventController) checkPlayerTeam(ctx *gin.Context, match model.Match, player model.Player, teamID uint) bool {
+	teamAt, err := playerTeamAt(c.DB, player, match.StartTime)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar time do jogador"})
+		return false
+	}
+	if teamAt != teamID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "O jogador não pertencia ao time na data da partida"})
+		return false
+	}
+	return true
+}
+
 func (c *MatchEventController) validateEventFields(event model.MatchEventCreate) error {
 	switch event.EventType {
 	case model.EventTypeGoal:
@@ -0,0 +1,442 @@
+package controller
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/config"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+)
+
+// csvFlushEvery é a quantidade de linhas escritas entre cada envio parcial do CSV
+const csvFlushEvery = 100
+
+// betFilters são os filtros da listagem e do extrato de apostas. Start e End
+// são dias; End inclui o dia inteiro.
+type betFilters struct {
+	Status  string
+	MatchID string
+	BetType string
+	Start   *time.Time
+	End     *time.Time
+}
+
+// parseBetFilters lê os filtros da query. Datas inválidas não são aplicadas e
+// são informadas no erro.
+func parseBetFilters(c *gin.Context) (betFilters, error) {
+	filters := betFilters{
+		Status:  c.Query("status"),
+		MatchID: c.Query("match_id"),
+		BetType: c.Query("bet_type"),
+	}
+
+	var invalid error
+	if startDate := c.Query("start_date"); startDate != "" {
+		start, err := time.ParseInLocation("2006-01-02", startDate, time.Local)
+		if err != nil {
+			invalid = fmt.Errorf("data inicial inválida, use o formato AAAA-MM-DD")
+		} else {
+			filters.Start = &start
+		}
+	}
+	if endDate := c.Query("end_date"); endDate != "" {
+		end, err := time.ParseInLocation("2006-01-02", endDate, time.Local)
+		if err != nil {
+			invalid = fmt.Errorf("data final inválida, use o formato AAAA-MM-DD")
+		} else {
+			end = end.AddDate(0, 0, 1)
+			filters.End = &end
+		}
+	}
+	return filters, invalid
+}
+
+// apply aplica os filtros à consulta de apostas
+func (f betFilters) apply(query *gorm.DB) *gorm.DB {
+	if f.Status != "" {
+		query = query.Where("status = ?", f.Status)
+	}
+	if f.MatchID != "" {
+		query = query.Where("match_id = ?", f.MatchID)
+	}
+	if f.BetType != "" {
+		query = query.Where("bet_type = ?", f.BetType)
+	}
+	if f.Start != nil {
+		query = query.Where("created_at >= ?", *f.Start)
+	}
+	if f.End != nil {
+		query = query.Where("created_at < ?", *f.End)
+	}
+	return query
+}
+
+// systemBetType identifica as apostas de sistema no filtro bet_type e no extrato
+const systemBetType = "system"
+
+// applySystem aplica os filtros à consulta de apostas de sistema. O filtro de
+// partida considera os palpites da aposta. O segundo retorno é falso quando o
+// filtro de tipo exclui as apostas de sistema.
+func (f betFilters) applySystem(query *gorm.DB) (*gorm.DB, bool) {
+	if f.BetType != "" && f.BetType != systemBetType {
+		return query, false
+	}
+	if f.Status != "" {
+		query = query.Where("status = ?", f.Status)
+	}
+	if f.MatchID != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM system_bet_legs l WHERE l.system_bet_id = system_bets.id AND l.match_id = ?)", f.MatchID)
+	}
+	if f.Start != nil {
+		query = query.Where("created_at >= ?", *f.Start)
+	}
+	if f.End != nil {
+		query = query.Where("created_at < ?", *f.End)
+	}
+	return query, true
+}
+
+// period descreve o período do extrato
+func (f betFilters) period() string {
+	start, end := "início", "hoje"
+	if f.Start != nil {
+		start = f.Start.Format("02/01/2006")
+	}
+	if f.End != nil {
+		end = f.End.AddDate(0, 0, -1).Format("02/01/2006")
+	}
+	return start + " a " + end
+}
+
+// statementSummary resume as apostas e a movimentação do saldo no período
+type statementSummary struct {
+	Bets          int
+	Pending       int
+	Staked        float64
+	PendingStaked float64
+	Returned      float64
+	Opening       float64
+	Credits       float64
+	Debits        float64
+	Closing       float64
+}
+
+// add soma uma aposta ao resumo. Apostas canceladas não entram: o valor
+// voltou integralmente ao saldo.
+func (s *statementSummary) add(bet model.Bet) {
+	if bet.Status == model.BetStatusCancelled {
+		return
+	}
+	s.Bets++
+	if bet.Status == model.BetStatusPending {
+		s.Pending++
+		s.PendingStaked += bet.Amount
+		return
+	}
+	s.Staked += bet.Amount
+	s.Returned += bet.Payout
+}
+
+// addSystem soma uma aposta de sistema ao resumo
+func (s *statementSummary) addSystem(bet model.SystemBet) {
+	s.Bets++
+	if bet.Status == model.BetStatusPending {
+		s.Pending++
+		s.PendingStaked += bet.TotalStake
+		return
+	}
+	s.Staked += bet.TotalStake
+	s.Returned += bet.Payout
+}
+
+// loadBalanceSummary calcula o saldo inicial, os créditos, os débitos e o
+// saldo final do período a partir do extrato de lançamentos
+func (s *statementSummary) loadBalanceSummary(db *gorm.DB, user model.User, filters betFilters) error {
+	after := 0.0
+	if filters.End != nil {
+		if err := db.Model(&model.LedgerEntry{}).
+			Where("user_id = ? AND created_at >= ?", user.ID, *filters.End).
+			Select("COALESCE(SUM(amount), 0)").Scan(&after).Error; err != nil {
+			return err
+		}
+	}
+
+	query := db.Model(&model.LedgerEntry{}).Where("user_id = ?", user.ID)
+	if filters.Start != nil {
+		query = query.Where("created_at >= ?", *filters.Start)
+	}
+	if filters.End != nil {
+		query = query.Where("created_at < ?", *filters.End)
+	}
+	var totals struct {
+		Credits float64
+		Debits  float64
+	}
+	if err := query.Select("COALESCE(SUM(CASE WHEN amount > 0 THEN amount END), 0) AS credits, " +
+		"COALESCE(SUM(CASE WHEN amount < 0 THEN amount END), 0) AS debits").
+		Scan(&totals).Error; err != nil {
+		return err
+	}
+
+	s.Closing = user.Balance - after
+	s.Credits = totals.Credits
+	s.Debits = totals.Debits
+	s.Opening = s.Closing - totals.Credits - totals.Debits
+	return nil
+}
+
+// betSelectionLabel descreve o palpite e a linha da aposta
+func betSelectionLabel(bet model.Bet) string {
+	if bet.Line == nil {
+		return bet.Selection
+	}
+	return fmt.Sprintf("%s %g", bet.Selection, *bet.Line)
+}
+
+// money formata um valor monetário com duas casas decimais
+func money(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+// ExportBets gera o extrato de apostas do usuário autenticado
+func ExportBets(c *gin.Context) {
+	user, _ := c.Get("user")
+	exportBets(c, user.(model.User))
+}
+
+// ExportUserBets gera o extrato de apostas de um usuário, para o suporte
+func ExportUserBets(c *gin.Context) {
+	var user model.User
+	if err := config.DB.First(&user, c.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar usuário"})
+		return
+	}
+	exportBets(c, user)
+}
+
+// exportBets gera o extrato em CSV (enviado aos poucos) ou em PDF, com os
+// mesmos filtros da listagem de apostas. As apostas de sistema vêm depois das
+// apostas simples, para que o resumo feche com a movimentação do saldo.
+func exportBets(c *gin.Context, user model.User) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "pdf" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato inválido, use csv ou pdf"})
+		return
+	}
+	filters, err := parseBetFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Lê o usuário de novo para o saldo estar atualizado
+	if err := config.DB.First(&user, user.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar usuário"})
+		return
+	}
+	var summary statementSummary
+	if err := summary.loadBalanceSummary(config.DB, user, filters); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular saldo do período", "details": err.Error()})
+		return
+	}
+
+	query := filters.apply(config.DB.Model(&model.Bet{}).Where("user_id = ?", user.ID)).Order("created_at, id")
+	systemQuery, withSystem := filters.applySystem(config.DB.Model(&model.SystemBet{}).Where("user_id = ?", user.ID))
+	systemQuery = systemQuery.Order("created_at, id")
+	if !withSystem {
+		systemQuery = nil
+	}
+	filename := fmt.Sprintf("extrato-apostas-%d-%s", user.ID, time.Now().Format("20060102"))
+	if format == "pdf" {
+		exportBetsPDF(c, user, query, systemQuery, filters, summary, filename)
+		return
+	}
+	exportBetsCSV(c, query, systemQuery, summary, filename)
+}
+
+// systemBetRow é a linha de uma aposta de sistema no extrato em CSV
+func systemBetRow(bet model.SystemBet) []string {
+	return []string{
+		bet.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+		strconv.FormatUint(uint64(bet.ID), 10),
+		"",
+		systemBetType,
+		bet.Type,
+		money(bet.TotalStake),
+		"",
+		bet.Status,
+		"",
+		money(bet.Payout),
+	}
+}
+
+// exportBetsCSV escreve o extrato em CSV à medida que lê as apostas. Sem
+// systemQuery, as apostas de sistema ficam de fora.
+func exportBetsCSV(c *gin.Context, query, systemQuery *gorm.DB, summary statementSummary, filename string) {
+	rows, err := query.Rows()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar apostas", "details": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"data", "aposta", "partida", "tipo", "palpite", "valor", "cotacao", "status", "resultado", "premio"})
+	count := 0
+	for rows.Next() {
+		var bet model.Bet
+		if err := config.DB.ScanRows(rows, &bet); err != nil {
+			// O cabeçalho já foi enviado; o erro fica registrado no próprio arquivo
+			w.Write([]string{"erro", err.Error()})
+			break
+		}
+		summary.add(bet)
+		w.Write([]string{
+			bet.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+			strconv.FormatUint(uint64(bet.ID), 10),
+			strconv.FormatUint(uint64(bet.MatchID), 10),
+			bet.BetType,
+			betSelectionLabel(bet),
+			money(bet.Amount),
+			money(bet.Odds),
+			bet.Status,
+			bet.Result,
+			money(bet.Payout),
+		})
+		count++
+		if count%csvFlushEvery == 0 {
+			w.Flush()
+			c.Writer.Flush()
+		}
+	}
+	rows.Close()
+
+	if systemQuery != nil {
+		var systemBets []model.SystemBet
+		if err := systemQuery.Find(&systemBets).Error; err != nil {
+			w.Write([]string{"erro", err.Error()})
+		}
+		for _, bet := range systemBets {
+			summary.addSystem(bet)
+			w.Write(systemBetRow(bet))
+		}
+	}
+
+	w.Write(nil)
+	for _, line := range [][]string{
+		{"resumo", "apostas", strconv.Itoa(summary.Bets)},
+		{"resumo", "pendentes", strconv.Itoa(summary.Pending), money(summary.PendingStaked)},
+		{"resumo", "apostado_liquidadas", money(summary.Staked)},
+		{"resumo", "retornado", money(summary.Returned)},
+		{"resumo", "resultado", money(summary.Returned - summary.Staked)},
+		{"saldo", "inicial", money(summary.Opening)},
+		{"saldo", "creditos", money(summary.Credits)},
+		{"saldo", "debitos", money(summary.Debits)},
+		{"saldo", "final", money(summary.Closing)},
+	} {
+		w.Write(line)
+	}
+	w.Flush()
+}
+
+// exportBetsPDF gera o extrato em PDF
+func exportBetsPDF(c *gin.Context, user model.User, query, systemQuery *gorm.DB, filters betFilters, summary statementSummary, filename string) {
+	var bets []model.Bet
+	if err := query.Find(&bets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar apostas", "details": err.Error()})
+		return
+	}
+	for _, bet := range bets {
+		summary.add(bet)
+	}
+	var systemBets []model.SystemBet
+	if systemQuery != nil {
+		if err := systemQuery.Find(&systemBets).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar apostas de sistema", "details": err.Error()})
+			return
+		}
+	}
+	for _, bet := range systemBets {
+		summary.addSystem(bet)
+	}
+
+	doc := util.NewPDFDocument()
+	doc.Text(util.PDFFontBold, 16, "Extrato de apostas")
+	doc.Text(util.PDFFontRegular, 10, fmt.Sprintf("%s <%s>", user.Name, user.Email))
+	doc.Text(util.PDFFontRegular, 10, "Período: "+filters.period())
+	doc.Text(util.PDFFontRegular, 10, "Gerado em "+time.Now().Format("02/01/2006 15:04"))
+	doc.Space(10)
+
+	doc.Text(util.PDFFontBold, 11, "Resumo do período")
+	doc.Text(util.PDFFontMono, 9, fmt.Sprintf("%-28s %14s", "Saldo inicial", money(summary.Opening)))
+	doc.Text(util.PDFFontMono, 9, fmt.Sprintf("%-28s %14s", "Créditos", money(summary.Credits)))
+	doc.Text(util.PDFFontMono, 9, fmt.Sprintf("%-28s %14s", "Débitos", money(summary.Debits)))
+	doc.Text(util.PDFFontMono, 9, fmt.Sprintf("%-28s %14s", "Saldo final", money(summary.Closing)))
+	doc.Space(4)
+	doc.Text(util.PDFFontMono, 9, fmt.Sprintf("%-28s %14d", "Apostas", summary.Bets))
+	doc.Text(util.PDFFontMono, 9, fmt.Sprintf("%-28s %14s", "Apostado (liquidadas)", money(summary.Staked)))
+	doc.Text(util.PDFFontMono, 9, fmt.Sprintf("%-28s %14s", "Retornado", money(summary.Returned)))
+	doc.Text(util.PDFFontMono, 9, fmt.Sprintf("%-28s %14s", "Resultado", money(summary.Returned-summary.Staked)))
+	doc.Text(util.PDFFontMono, 9, fmt.Sprintf("%-28s %14s", fmt.Sprintf("Pendentes (%d)", summary.Pending), money(summary.PendingStaked)))
+	doc.Space(10)
+
+	doc.Text(util.PDFFontBold, 11, "Apostas")
+	const row = "%-16s %7s %7s %-16s %-11s %10s %6s %-9s %-6s %10s"
+	doc.Text(util.PDFFontMono, 7.5, fmt.Sprintf(row, "Data", "Aposta", "Partida", "Tipo", "Palpite", "Valor", "Cot.", "Status", "Result", "Prêmio"))
+	for _, bet := range bets {
+		doc.Text(util.PDFFontMono, 7.5, fmt.Sprintf(row,
+			bet.CreatedAt.Local().Format("02/01/2006 15:04"),
+			strconv.FormatUint(uint64(bet.ID), 10),
+			strconv.FormatUint(uint64(bet.MatchID), 10),
+			bet.BetType,
+			betSelectionLabel(bet),
+			money(bet.Amount),
+			money(bet.Odds),
+			bet.Status,
+			bet.Result,
+			money(bet.Payout),
+		))
+	}
+	if len(bets) == 0 {
+		doc.Text(util.PDFFontRegular, 9, "Nenhuma aposta no período.")
+	}
+
+	if len(systemBets) > 0 {
+		doc.Space(10)
+		doc.Text(util.PDFFontBold, 11, "Apostas de sistema")
+		doc.Text(util.PDFFontMono, 7.5, fmt.Sprintf(row, "Data", "Aposta", "", "Sistema", "", "Valor", "", "Status", "", "Prêmio"))
+		for _, bet := range systemBets {
+			doc.Text(util.PDFFontMono, 7.5, fmt.Sprintf(row,
+				bet.CreatedAt.Local().Format("02/01/2006 15:04"),
+				strconv.FormatUint(uint64(bet.ID), 10),
+				"",
+				bet.Type,
+				"",
+				money(bet.TotalStake),
+				"",
+				bet.Status,
+				"",
+				money(bet.Payout),
+			))
+		}
+	}
+
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, filename))
+	c.Status(http.StatusOK)
+	doc.WriteTo(c.Writer)
+}
@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errRegistrationRejected interrompe a transação de inscrição quando uma regra do torneio é violada
+var errRegistrationRejected = errors.New("inscrição recusada")
+
+// teamsRegistered informa se todos os times estão inscritos no torneio
+func teamsRegistered(db *gorm.DB, tournamentID uint, teamIDs ...uint) (bool, error) {
+	unique := make(map[uint]bool, len(teamIDs))
+	for _, id := range teamIDs {
+		unique[id] = true
+	}
+
+	var count int64
+	if err := db.Model(&model.TournamentTeam{}).
+		Where("tournament_id = ? AND team_id IN ?", tournamentID, teamIDs).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return int(count) == len(unique), nil
+}
+
+// ListTournamentTeams lista os times inscritos no torneio
+func (c *TournamentController) ListTournamentTeams(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var tournament model.Tournament
+	if err := c.DB.First(&tournament, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Torneio não encontrado"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar torneio"})
+		return
+	}
+
+	teams := []model.TournamentTeamResponse{}
+	if err := c.DB.Table("tournament_teams").
+		Select("teams.id AS team_id, teams.name, teams.country, teams.city, teams.stadium, tournament_teams.joined_at").
+		Joins("JOIN teams ON teams.id = tournament_teams.team_id").
+		Where("tournament_teams.tournament_id = ?", tournament.ID).
+		Order("tournament_teams.joined_at, teams.id").
+		Scan(&teams).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar times do torneio"})
+		return
+	}
+
+	meta := gin.H{
+		"total":                 len(teams),
+		"max_teams":             tournament.MaxTeams,
+		"registration_deadline": tournament.RegistrationDeadline,
+	}
+	if tournament.MaxTeams > 0 {
+		meta["spots_left"] = tournament.MaxTeams - len(teams)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": teams, "meta": meta})
+}
+
+// RegisterTournamentTeam inscreve um time no torneio, respeitando o prazo de
+// inscrição e o limite de vagas
+func (c *TournamentController) RegisterTournamentTeam(ctx *gin.Context) {
+	var input model.TournamentTeamRegister
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	var team model.Team
+	if err := c.DB.First(&team, input.TeamID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Time não encontrado"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar time"})
+		return
+	}
+	if team.Status != "active" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Apenas times ativos podem ser inscritos"})
+		return
+	}
+
+	var registration model.TournamentTeam
+	status := http.StatusCreated
+	message := ""
+	err := c.DB.Transaction(func(tx *gorm.DB) error {
+		// Bloqueia o torneio para que inscrições simultâneas não ultrapassem o limite
+		var tournament model.Tournament
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tournament, ctx.Param("id")).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				status, message = http.StatusNotFound, "Torneio não encontrado"
+			}
+			return err
+		}
+
+		if tournament.Status == "completed" || tournament.Status == "cancelled" {
+			status, message = http.StatusConflict, "Não é possível inscrever times em um torneio encerrado"
+			return errRegistrationRejected
+		}
+		if tournament.RegistrationDeadline != nil && time.Now().After(*tournament.RegistrationDeadline) {
+			status, message = http.StatusConflict, "O prazo de inscrição do torneio terminou"
+			return errRegistrationRejected
+		}
+
+		var existing int64
+		if err := tx.Model(&model.TournamentTeam{}).
+			Where("tournament_id = ? AND team_id = ?", tournament.ID, team.ID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			status, message = http.StatusConflict, "Time já inscrito neste torneio"
+			return errRegistrationRejected
+		}
+
+		if tournament.MaxTeams > 0 {
+			var registered int64
+			if err := tx.Model(&model.TournamentTeam{}).Where("tournament_id = ?", tournament.ID).
+				Count(&registered).Error; err != nil {
+				return err
+			}
+			if int(registered) >= tournament.MaxTeams {
+				status, message = http.StatusConflict, fmt.Sprintf("O torneio já atingiu o limite de %d times", tournament.MaxTeams)
+				return errRegistrationRejected
+			}
+		}
+
+		registration = model.TournamentTeam{TournamentID: tournament.ID, TeamID: team.ID, JoinedAt: time.Now()}
+		return tx.Create(&registration).Error
+	})
+	if err != nil {
+		if message == "" {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao inscrever time", "details": err.Error()})
+			return
+		}
+		ctx.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	invalidateStandings(c.Cache, registration.TournamentID)
+
+	ctx.JSON(http.StatusCreated, model.TournamentTeamResponse{
+		TeamID:   team.ID,
+		Name:     team.Name,
+		Country:  team.Country,
+		City:     team.City,
+		Stadium:  team.Stadium,
+		JoinedAt: registration.JoinedAt,
+	})
+}
+
+// UnregisterTournamentTeam remove a inscrição de um time que ainda não tem
+// partidas no torneio
+func (c *TournamentController) UnregisterTournamentTeam(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var tournament model.Tournament
+	if err := c.DB.First(&tournament, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Torneio não encontrado"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar torneio"})
+		return
+	}
+
+	var registration model.TournamentTeam
+	if err := c.DB.Where("tournament_id = ? AND team_id = ?", tournament.ID, ctx.Param("team_id")).
+		First(&registration).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Time não inscrito neste torneio"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar inscrição"})
+		return
+	}
+
+	// Um time com partidas no torneio não pode sair da competição
+	var matchCount int64
+	if err := c.DB.Model(&model.Match{}).
+		Where("tournament_id = ? AND (home_team_id = ? OR away_team_id = ?) AND status <> ?",
+			tournament.ID, registration.TeamID, registration.TeamID, model.MatchStatusCancelled).
+		Count(&matchCount).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar partidas do time"})
+		return
+	}
+	if matchCount > 0 {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Não é possível remover um time com partidas no torneio"})
+		return
+	}
+
+	if err := c.DB.Where("tournament_id = ? AND team_id = ?", registration.TournamentID, registration.TeamID).
+		Delete(&model.TournamentTeam{}).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover time do torneio"})
+		return
+	}
+
+	invalidateStandings(c.Cache, tournament.ID)
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Time removido do torneio com sucesso"})
+}
@@ -0,0 +1,406 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errBetLocked          = errors.New("a aposta não pode ser alterada na situação atual")
+	errBetUnchanged       = errors.New("a aposta já está nessa situação")
+	errMatchNotFinished   = errors.New("a partida ainda não foi encerrada; informe a situação da aposta")
+	errOutcomeUnavailable = errors.New("não foi possível determinar o resultado da aposta com os dados da partida; informe a situação da aposta")
+	errNegativeBalance    = errors.New("o ajuste deixaria o saldo do usuário negativo")
+)
+
+type BackOfficeController struct {
+	DB *gorm.DB
+}
+
+func NewBackOfficeController(db *gorm.DB) *BackOfficeController {
+	return &BackOfficeController{DB: db}
+}
+
+// checkReasonNotes exige a descrição quando o motivo é "other"
+func checkReasonNotes(reasonCode, notes string) error {
+	if reasonCode == model.ReasonCodeOther && notes == "" {
+		return errors.New("Informe as observações quando o motivo for other")
+	}
+	return nil
+}
+
+// auditSnapshot serializa o estado do alvo para a trilha de auditoria
+func auditSnapshot(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// markSettled registra a data da primeira liquidação da aposta
+func markSettled(bet *model.Bet) {
+	if bet.SettledAt == nil {
+		now := time.Now()
+		bet.SettledAt = &now
+	}
+}
+
+// betAuditState é o estado da aposta guardado antes e depois da intervenção
+func betAuditState(bet model.Bet) gin.H {
+	return gin.H{"status": bet.Status, "result": bet.Result, "payout": bet.Payout, "settled_manually": bet.SettledManually}
+}
+
+// lockAdminBet carrega a aposta com bloqueio e verifica se ela pode sofrer
+// intervenção: pendente ou liquidada, e sem saque antecipado
+func lockAdminBet(tx *gorm.DB, id string) (model.Bet, error) {
+	var bet model.Bet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Selections").First(&bet, id).Error; err != nil {
+		return bet, err
+	}
+	if bet.IsCashout {
+		return bet, errBetLocked
+	}
+	if bet.Status != model.BetStatusPending {
+		settled := false
+		for _, s := range settledStatuses {
+			if bet.Status == s {
+				settled = true
+			}
+		}
+		if !settled {
+			return bet, errBetLocked
+		}
+	}
+	return bet, nil
+}
+
+// respondBackOfficeError traduz os erros das intervenções em respostas HTTP
+func respondBackOfficeError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Registro não encontrado"})
+	case errors.Is(err, errBetLocked), errors.Is(err, errBetUnchanged), errors.Is(err, errMatchNotFinished),
+		errors.Is(err, errOutcomeUnavailable), errors.Is(err, errNegativeBalance):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": message, "details": err.Error()})
+	}
+}
+
+// VoidBet anula uma aposta pendente ou já liquidada. O usuário fica com o
+// valor apostado, exceto a parte coberta por bônus; a diferença para o que
+// já foi creditado é lançada no saldo.
+func (c *BackOfficeController) VoidBet(ctx *gin.Context) {
+	var input model.AdminBetVoid
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := checkReasonNotes(input.ReasonCode, input.Notes); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID := ctx.GetUint("user_id")
+	var audit model.AdminAuditLog
+	err := c.DB.Transaction(func(tx *gorm.DB) error {
+		bet, err := lockAdminBet(tx, ctx.Param("id"))
+		if err != nil {
+			return err
+		}
+		before := betAuditState(bet)
+
+		refund := math.Max(bet.Amount-bet.BonusApplied, 0)
+		delta := math.Round((refund-bet.Payout)*100) / 100
+		entryType := model.LedgerTypeCorrection
+		if bet.Status == model.BetStatusPending {
+			entryType = model.LedgerTypeBetRefund
+		}
+		if delta != 0 {
+			betID := bet.ID
+			if err := adjustBalance(tx, bet.UserID, &betID, entryType, delta,
+				fmt.Sprintf("Anulação manual da aposta #%d (%s)", bet.ID, input.ReasonCode)); err != nil {
+				return err
+			}
+		}
+
+		bet.Status = model.BetStatusVoid
+		bet.Payout = refund
+		bet.SettledManually = true
+		markSettled(&bet)
+		if err := tx.Omit(clause.Associations).Save(&bet).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&model.Notification{
+			UserID:  bet.UserID,
+			Type:    model.NotificationTypeBetVoided,
+			Title:   "Aposta anulada",
+			Message: fmt.Sprintf("Sua aposta #%d foi anulada pelo suporte. Ajuste no saldo: R$ %.2f.", bet.ID, delta),
+		}).Error; err != nil {
+			return err
+		}
+		if err := refreshRiskPositions(tx, bet.MatchID); err != nil {
+			return err
+		}
+
+		audit = model.AdminAuditLog{
+			AdminID:    adminID,
+			Action:     model.AuditActionBetVoid,
+			TargetType: "bet",
+			TargetID:   bet.ID,
+			UserID:     bet.UserID,
+			ReasonCode: input.ReasonCode,
+			Notes:      input.Notes,
+			Amount:     delta,
+			Before:     auditSnapshot(before),
+			After:      auditSnapshot(betAuditState(bet)),
+		}
+		return tx.Create(&audit).Error
+	})
+	if err != nil {
+		respondBackOfficeError(ctx, err, "Erro ao anular aposta")
+		return
+	}
+	invalidateUserAnalytics(audit.UserID)
+
+	ctx.JSON(http.StatusOK, audit)
+}
+
+// SettleBet liquida uma aposta pendente ou corrige a liquidação de uma já
+// liquidada. Com status, a situação é definida pelo suporte; sem status, a
+// aposta é liquidada pelos dados atuais da partida, que precisa estar
+// encerrada. A diferença de prêmio é lançada no saldo do usuário. A aposta
+// fica marcada como liquidada manualmente e deixa de ser reliquidada pelas
+// correções da partida.
+func (c *BackOfficeController) SettleBet(ctx *gin.Context) {
+	var input model.AdminBetSettle
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := checkReasonNotes(input.ReasonCode, input.Notes); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID := ctx.GetUint("user_id")
+	var audit model.AdminAuditLog
+	err := c.DB.Transaction(func(tx *gorm.DB) error {
+		bet, err := lockAdminBet(tx, ctx.Param("id"))
+		if err != nil {
+			return err
+		}
+		before := betAuditState(bet)
+
+		status := input.Status
+		result := bet.Result
+		if status == "" {
+			var match model.Match
+			if err := tx.First(&match, bet.MatchID).Error; err != nil {
+				return err
+			}
+			if match.Status != model.MatchStatusFinished {
+				return errMatchNotFinished
+			}
+			events, stats, err := settlementData(tx, match.ID)
+			if err != nil {
+				return err
+			}
+			outcome, ok := betOutcome(bet, match, events, stats)
+			if !ok {
+				return errOutcomeUnavailable
+			}
+			status = outcome
+			result = matchResult(match)
+		}
+		if status == bet.Status && result == bet.Result {
+			return errBetUnchanged
+		}
+
+		payout := betPayout(bet, status)
+		delta := math.Round((payout-bet.Payout)*100) / 100
+		entryType := model.LedgerTypeCorrection
+		if bet.Status == model.BetStatusPending {
+			entryType = payoutLedgerType(status)
+		}
+		if delta != 0 {
+			betID := bet.ID
+			if err := adjustBalance(tx, bet.UserID, &betID, entryType, delta,
+				fmt.Sprintf("Liquidação manual da aposta #%d (%s): %s", bet.ID, status, input.ReasonCode)); err != nil {
+				return err
+			}
+		}
+
+		bet.Status = status
+		bet.Result = result
+		bet.Payout = payout
+		bet.SettledManually = true
+		markSettled(&bet)
+		if err := tx.Omit(clause.Associations).Save(&bet).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&model.Notification{
+			UserID:  bet.UserID,
+			Type:    model.NotificationTypeBetSettled,
+			Title:   "Aposta liquidada",
+			Message: fmt.Sprintf("Sua aposta #%d foi liquidada pelo suporte (%s). Ajuste no saldo: R$ %.2f.", bet.ID, status, delta),
+		}).Error; err != nil {
+			return err
+		}
+		if err := refreshRiskPositions(tx, bet.MatchID); err != nil {
+			return err
+		}
+
+		audit = model.AdminAuditLog{
+			AdminID:    adminID,
+			Action:     model.AuditActionBetSettle,
+			TargetType: "bet",
+			TargetID:   bet.ID,
+			UserID:     bet.UserID,
+			ReasonCode: input.ReasonCode,
+			Notes:      input.Notes,
+			Amount:     delta,
+			Before:     auditSnapshot(before),
+			After:      auditSnapshot(betAuditState(bet)),
+		}
+		return tx.Create(&audit).Error
+	})
+	if err != nil {
+		respondBackOfficeError(ctx, err, "Erro ao liquidar aposta")
+		return
+	}
+	invalidateUserAnalytics(audit.UserID)
+
+	ctx.JSON(http.StatusOK, audit)
+}
+
+// AdjustBalance lança um crédito ou débito manual no saldo do usuário. O
+// débito não pode deixar o saldo negativo.
+func (c *BackOfficeController) AdjustBalance(ctx *gin.Context) {
+	var input model.AdminBalanceAdjustment
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := checkReasonNotes(input.ReasonCode, input.Notes); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	amount := math.Round(input.Amount*100) / 100
+	if amount == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "O valor do ajuste deve ser diferente de zero"})
+		return
+	}
+
+	adminID := ctx.GetUint("user_id")
+	var audit model.AdminAuditLog
+	err := c.DB.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, ctx.Param("id")).Error; err != nil {
+			return err
+		}
+		balance := math.Round((user.Balance+amount)*100) / 100
+		if balance < 0 {
+			return errNegativeBalance
+		}
+
+		if err := adjustBalance(tx, user.ID, nil, model.LedgerTypeManualAdjustment, amount,
+			fmt.Sprintf("Ajuste manual de saldo (%s)", input.ReasonCode)); err != nil {
+			return err
+		}
+		if err := tx.Create(&model.Notification{
+			UserID:  user.ID,
+			Type:    model.NotificationTypeBalanceAdjusted,
+			Title:   "Saldo ajustado",
+			Message: fmt.Sprintf("Seu saldo foi ajustado pelo suporte em R$ %.2f. Novo saldo: R$ %.2f.", amount, balance),
+		}).Error; err != nil {
+			return err
+		}
+
+		audit = model.AdminAuditLog{
+			AdminID:    adminID,
+			Action:     model.AuditActionBalanceAdjustment,
+			TargetType: "user",
+			TargetID:   user.ID,
+			UserID:     user.ID,
+			ReasonCode: input.ReasonCode,
+			Notes:      input.Notes,
+			Amount:     amount,
+			Before:     auditSnapshot(gin.H{"balance": user.Balance}),
+			After:      auditSnapshot(gin.H{"balance": balance}),
+		}
+		return tx.Create(&audit).Error
+	})
+	if err != nil {
+		respondBackOfficeError(ctx, err, "Erro ao ajustar saldo")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, audit)
+}
+
+// ListAuditLogs lista a trilha de auditoria das intervenções, com filtros
+// opcionais por administrador, usuário afetado, ação, alvo e motivo
+func (c *BackOfficeController) ListAuditLogs(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := c.DB.Model(&model.AdminAuditLog{})
+	filters := map[string]string{
+		"admin_id":    "admin_id = ?",
+		"user_id":     "user_id = ?",
+		"action":      "action = ?",
+		"target_type": "target_type = ?",
+		"target_id":   "target_id = ?",
+		"reason_code": "reason_code = ?",
+	}
+	for param, condition := range filters {
+		if value := ctx.Query(param); value != "" {
+			query = query.Where(condition, value)
+		}
+	}
+
+	var total int64
+	query.Count(&total)
+
+	logs := []model.AdminAuditLog{}
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&logs).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar trilha de auditoria", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": logs,
+		"meta": gin.H{
+			"total":  total,
+			"page":   page,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/lfdelima3/Backend-Go-Bet/src/config"
 	"github.com/lfdelima3/Backend-Go-Bet/src/model"
 	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
 )
 
 // CreatePlayer cria um novo jogador
@@ -51,7 +52,14 @@ func CreatePlayer(c *gin.Context) {
 		Status:      "active",
 	}
 
-	if err := config.DB.Create(&newPlayer).Error; err != nil {
+	// O primeiro vínculo não tem data de início, pois vale para partidas já cadastradas
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newPlayer).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.PlayerSpell{PlayerID: newPlayer.ID, TeamID: newPlayer.TeamID}).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar jogador", "details": err.Error()})
 		return
 	}
@@ -158,19 +166,13 @@ func UpdatePlayer(c *gin.Context) {
 		}
 	}
 
-	// Se o time estiver sendo alterado, verificar se o novo time existe
+	// A troca de time preserva o histórico e por isso é feita pela rota de transferências
 	if updateData.TeamID != 0 && updateData.TeamID != player.TeamID {
-		var team model.Team
-		if err := config.DB.First(&team, updateData.TeamID).Error; err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Time não encontrado"})
-			return
-		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Use POST /players/:id/transfers para mudar o time do jogador"})
+		return
 	}
 
 	// Atualizar apenas os campos fornecidos
-	if updateData.TeamID != 0 {
-		player.TeamID = updateData.TeamID
-	}
 	if updateData.Name != "" {
 		player.Name = updateData.Name
 	}
@@ -214,7 +216,8 @@ func DeletePlayer(c *gin.Context) {
 		return
 	}
 
-	// Verificar se o jogador está participando de alguma partida em andamento
+	// Jogadores com histórico (eventos, escalações ou transferências) não podem
+	// ser removidos, pois esses registros continuam apontando para eles
 	var count int64
 	if err := config.DB.Model(&model.MatchEvent{}).Where("player_id = ? OR sub_in_player_id = ? OR sub_out_player_id = ?", id, id, id).Count(&count).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar participação do jogador", "details": err.Error()})
@@ -225,7 +228,33 @@ func DeletePlayer(c *gin.Context) {
 		return
 	}
 
-	if err := config.DB.Delete(&player).Error; err != nil {
+	if err := config.DB.Model(&model.MatchLineupPlayer{}).Where("player_id = ?", player.ID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar escalações do jogador", "details": err.Error()})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Não é possível deletar um jogador que já foi escalado"})
+		return
+	}
+
+	// O vínculo original, criado no cadastro, não é uma transferência; qualquer
+	// outro período faz parte do histórico do jogador
+	if err := config.DB.Model(&model.PlayerSpell{}).Where("player_id = ? AND start_date IS NOT NULL", player.ID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar transferências do jogador", "details": err.Error()})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Não é possível deletar um jogador com transferências registradas"})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("player_id = ? AND start_date IS NULL", player.ID).Delete(&model.PlayerSpell{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&player).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar jogador", "details": err.Error()})
 		return
 	}
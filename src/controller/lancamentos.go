@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"gorm.io/gorm"
+)
+
+// errInsufficientBalance indica que o saldo do usuário não cobre o débito
+var errInsufficientBalance = errors.New("saldo insuficiente")
+
+// recordLedger registra um lançamento no extrato sem alterar o saldo, para os
+// fluxos que já atualizam o usuário diretamente
+func recordLedger(tx *gorm.DB, userID uint, betID *uint, entryType string, amount float64, description string) error {
+	return tx.Create(&model.LedgerEntry{
+		UserID:      userID,
+		BetID:       betID,
+		Type:        entryType,
+		Amount:      amount,
+		Description: description,
+	}).Error
+}
+
+// adjustBalance altera o saldo do usuário e registra o lançamento correspondente
+func adjustBalance(tx *gorm.DB, userID uint, betID *uint, entryType string, amount float64, description string) error {
+	if err := tx.Model(&model.User{}).Where("id = ?", userID).
+		UpdateColumn("balance", gorm.Expr("balance + ?", amount)).Error; err != nil {
+		return err
+	}
+	return recordLedger(tx, userID, betID, entryType, amount, description)
+}
+
+// debitBalance debita o valor do saldo do usuário e registra o lançamento. O
+// débito só é feito se o saldo cobrir o valor, para que requisições
+// simultâneas não deixem o saldo negativo.
+func debitBalance(tx *gorm.DB, userID uint, betID *uint, entryType string, amount float64, description string) error {
+	result := tx.Model(&model.User{}).Where("id = ? AND balance >= ?", userID, amount).
+		UpdateColumn("balance", gorm.Expr("balance - ?", amount))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errInsufficientBalance
+	}
+	return recordLedger(tx, userID, betID, entryType, -amount, description)
+}
@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+)
+
+// stakeLimits retorna os menores limites de aposta e de prêmio configurados
+// para o mercado no torneio, globais ou específicos. Sem limite, o valor
+// retornado é infinito.
+func stakeLimits(db *gorm.DB, tournamentID uint, market string) (float64, float64, error) {
+	var limits []model.StakeLimit
+	if err := db.Where("(tournament_id IS NULL OR tournament_id = ?) AND (market IS NULL OR market = '' OR market = ?)",
+		tournamentID, market).Find(&limits).Error; err != nil {
+		return 0, 0, err
+	}
+
+	maxStake, maxPayout := math.Inf(1), math.Inf(1)
+	for _, l := range limits {
+		if l.MaxStake > 0 {
+			maxStake = math.Min(maxStake, l.MaxStake)
+		}
+		if l.MaxPayout > 0 {
+			maxPayout = math.Min(maxPayout, l.MaxPayout)
+		}
+	}
+	return maxStake, maxPayout, nil
+}
+
+// maxAllowedStake calcula o maior valor que o usuário pode apostar na opção,
+// combinando os limites globais, do torneio e do mercado com o fator do
+// usuário. O fator multiplica o limite de aposta; o limite de prêmio,
+// convertido em valor de aposta pela cotação, só pode ser reduzido por ele,
+// nunca ampliado. Sem limites configurados vale o limite padrão da
+// configuração, para que o fator do usuário sempre tenha efeito. O segundo
+// retorno é falso quando nenhum limite se aplica (limite padrão zerado).
+func maxAllowedStake(db *gorm.DB, user model.User, match model.Match, market string, odds float64) (float64, bool, error) {
+	// Fator zero bloqueia as apostas do usuário mesmo sem limites configurados
+	if user.StakeFactor == 0 {
+		return 0, true, nil
+	}
+
+	maxStake, maxPayout, err := stakeLimits(db, match.TournamentID, market)
+	if err != nil {
+		return 0, false, err
+	}
+	if odds <= 0 {
+		maxPayout = math.Inf(1)
+	}
+	if math.IsInf(maxStake, 1) && math.IsInf(maxPayout, 1) {
+		maxStake = bettingConfig.DefaultMaxStake
+		if maxStake <= 0 {
+			return 0, false, nil
+		}
+	}
+
+	limit := maxStake * user.StakeFactor
+	if !math.IsInf(maxPayout, 1) {
+		limit = math.Min(limit, maxPayout/odds*math.Min(user.StakeFactor, 1))
+	}
+	return math.Floor(limit*100) / 100, true, nil
+}
+
+type StakeLimitController struct {
+	DB *gorm.DB
+}
+
+func NewStakeLimitController(db *gorm.DB) *StakeLimitController {
+	return &StakeLimitController{DB: db}
+}
+
+// bindStakeLimit lê e valida os dados de um limite
+func (c *StakeLimitController) bindStakeLimit(ctx *gin.Context) (model.StakeLimitInput, bool) {
+	var input model.StakeLimitInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return input, false
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return input, false
+	}
+	if input.MaxStake == 0 && input.MaxPayout == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Informe o valor máximo de aposta e/ou de prêmio"})
+		return input, false
+	}
+	if input.TournamentID != nil {
+		var tournament model.Tournament
+		if err := c.DB.First(&tournament, *input.TournamentID).Error; err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Torneio não encontrado"})
+			return input, false
+		}
+	}
+	return input, true
+}
+
+// ListStakeLimits lista os limites de aposta, com filtro opcional de torneio
+func (c *StakeLimitController) ListStakeLimits(ctx *gin.Context) {
+	query := c.DB.Model(&model.StakeLimit{})
+	if tournamentID := ctx.Query("tournament_id"); tournamentID != "" {
+		query = query.Where("tournament_id = ?", tournamentID)
+	}
+
+	limits := []model.StakeLimit{}
+	if err := query.Order("id").Find(&limits).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar limites de aposta"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, limits)
+}
+
+// CreateStakeLimit cadastra um limite de aposta
+func (c *StakeLimitController) CreateStakeLimit(ctx *gin.Context) {
+	input, ok := c.bindStakeLimit(ctx)
+	if !ok {
+		return
+	}
+
+	limit := model.StakeLimit{
+		TournamentID: input.TournamentID,
+		Market:       input.Market,
+		MaxStake:     input.MaxStake,
+		MaxPayout:    input.MaxPayout,
+		Description:  input.Description,
+	}
+	if err := c.DB.Create(&limit).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar limite de aposta", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, limit)
+}
+
+// UpdateStakeLimit substitui os valores de um limite de aposta
+func (c *StakeLimitController) UpdateStakeLimit(ctx *gin.Context) {
+	var limit model.StakeLimit
+	if err := c.DB.First(&limit, ctx.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Limite de aposta não encontrado"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar limite de aposta"})
+		return
+	}
+
+	input, ok := c.bindStakeLimit(ctx)
+	if !ok {
+		return
+	}
+
+	limit.TournamentID = input.TournamentID
+	limit.Market = input.Market
+	limit.MaxStake = input.MaxStake
+	limit.MaxPayout = input.MaxPayout
+	limit.Description = input.Description
+	if err := c.DB.Save(&limit).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar limite de aposta", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, limit)
+}
+
+// DeleteStakeLimit remove um limite de aposta
+func (c *StakeLimitController) DeleteStakeLimit(ctx *gin.Context) {
+	result := c.DB.Delete(&model.StakeLimit{}, ctx.Param("id"))
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover limite de aposta"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Limite de aposta não encontrado"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Limite de aposta removido com sucesso"})
+}
+
+// SetUserStakeFactor altera o fator de limite de aposta de um usuário.
+// Fatores menores que 1 reduzem os limites; zero bloqueia novas apostas.
+func (c *StakeLimitController) SetUserStakeFactor(ctx *gin.Context) {
+	var user model.User
+	if err := c.DB.First(&user, ctx.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar usuário"})
+		return
+	}
+
+	var input model.UserStakeFactor
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if err := c.DB.Model(&user).Update("stake_factor", *input.StakeFactor).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar fator de aposta"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"user_id": user.ID, "stake_factor": *input.StakeFactor})
+}
@@ -0,0 +1,255 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"gorm.io/gorm"
+)
+
+// periodRange é o intervalo de minutos de um período da partida
+type periodRange struct {
+	Order int
+	First int
+	Last  int
+	Label string
+}
+
+// matchPeriods define a ordem e os minutos de cada período. Os acréscimos só
+// podem ser informados no último minuto do período (45+3, 90+5...).
+var matchPeriods = map[string]periodRange{
+	model.PeriodFirstHalf:       {Order: 1, First: 1, Last: 45, Label: "1º tempo"},
+	model.PeriodSecondHalf:      {Order: 2, First: 46, Last: 90, Label: "2º tempo"},
+	model.PeriodExtraTimeFirst:  {Order: 3, First: 91, Last: 105, Label: "1º tempo da prorrogação"},
+	model.PeriodExtraTimeSecond: {Order: 4, First: 106, Last: 120, Label: "2º tempo da prorrogação"},
+	model.PeriodPenalties:       {Order: 5, First: 120, Last: 120, Label: "Disputa de pênaltis"},
+}
+
+// periodForMinute deduz o período a partir do minuto, sem considerar pênaltis
+func periodForMinute(minute int) string {
+	switch {
+	case minute <= 45:
+		return model.PeriodFirstHalf
+	case minute <= 90:
+		return model.PeriodSecondHalf
+	case minute <= 105:
+		return model.PeriodExtraTimeFirst
+	default:
+		return model.PeriodExtraTimeSecond
+	}
+}
+
+// normalizeEventTiming valida período, minuto e acréscimos de um evento.
+// Sem período informado, ele é deduzido do minuto.
+func normalizeEventTiming(period string, minute, added int) (string, error) {
+	if period == "" {
+		period = periodForMinute(minute)
+	}
+	bounds, ok := matchPeriods[period]
+	if !ok {
+		return "", fmt.Errorf("período inválido")
+	}
+	if minute < bounds.First || minute > bounds.Last {
+		return "", fmt.Errorf("o minuto %d não pertence ao %s", minute, bounds.Label)
+	}
+	if added > 0 && (period == model.PeriodPenalties || minute != bounds.Last) {
+		return "", fmt.Errorf("acréscimos só podem ser informados no minuto %d do %s", bounds.Last, bounds.Label)
+	}
+	return period, nil
+}
+
+// sortEvents ordena os eventos cronologicamente: período, minuto, acréscimos
+// e ordem de registro
+func sortEvents(events []model.MatchEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if pa, pb := matchPeriods[a.Period].Order, matchPeriods[b.Period].Order; pa != pb {
+			return pa < pb
+		}
+		if a.Minute != b.Minute {
+			return a.Minute < b.Minute
+		}
+		if a.AddedMinute != b.AddedMinute {
+			return a.AddedMinute < b.AddedMinute
+		}
+		return a.ID < b.ID
+	})
+}
+
+// eventClock formata o minuto do evento para exibição
+func eventClock(event model.MatchEvent) string {
+	if event.Period == model.PeriodPenalties {
+		return "Pên."
+	}
+	if event.AddedMinute > 0 {
+		return fmt.Sprintf("%d+%d'", event.Minute, event.AddedMinute)
+	}
+	return fmt.Sprintf("%d'", event.Minute)
+}
+
+// goalSide retorna o lado (home ou away) beneficiado por um gol. Gols contra
+// contam para o adversário do time do jogador.
+func goalSide(event model.MatchEvent, match model.Match) string {
+	scoredFor := event.TeamID
+	if event.GoalType == model.GoalTypeOwnGoal {
+		if event.TeamID == match.HomeTeamID {
+			scoredFor = match.AwayTeamID
+		} else {
+			scoredFor = match.HomeTeamID
+		}
+	}
+	if scoredFor == match.HomeTeamID {
+		return model.SelectionHome
+	}
+	return model.SelectionAway
+}
+
+// firstGoalSide retorna o lado que marcou o primeiro gol da partida, ou none.
+// Os eventos precisam estar ordenados; a disputa de pênaltis não conta.
+func firstGoalSide(events []model.MatchEvent, match model.Match) string {
+	for _, e := range events {
+		if e.EventType == model.EventTypeGoal && e.Period != model.PeriodPenalties {
+			return goalSide(e, match)
+		}
+	}
+	return model.SelectionNone
+}
+
+// halfTimeScore calcula o placar do primeiro tempo, incluindo os acréscimos
+func halfTimeScore(events []model.MatchEvent, match model.Match) (int, int) {
+	home, away := 0, 0
+	for _, e := range events {
+		if e.EventType != model.EventTypeGoal || e.Period != model.PeriodFirstHalf {
+			continue
+		}
+		if goalSide(e, match) == model.SelectionHome {
+			home++
+		} else {
+			away++
+		}
+	}
+	return home, away
+}
+
+// timelineText descreve o evento em linguagem natural
+func timelineText(entry model.TimelineEntry, event model.MatchEvent) string {
+	switch event.EventType {
+	case model.EventTypeGoal:
+		switch {
+		case event.Period == model.PeriodPenalties:
+			return fmt.Sprintf("%s converte a cobrança para o %s", entry.PlayerName, entry.TeamName)
+		case event.GoalType == model.GoalTypeOwnGoal:
+			return fmt.Sprintf("Gol contra de %s (%s)", entry.PlayerName, entry.TeamName)
+		case event.GoalType == model.GoalTypePenalty:
+			return fmt.Sprintf("Gol de pênalti de %s (%s)", entry.PlayerName, entry.TeamName)
+		}
+		return fmt.Sprintf("Gol de %s (%s)", entry.PlayerName, entry.TeamName)
+	case model.EventTypeCard:
+		if event.CardType == model.CardTypeRed {
+			return fmt.Sprintf("Cartão vermelho para %s (%s)", entry.PlayerName, entry.TeamName)
+		}
+		return fmt.Sprintf("Cartão amarelo para %s (%s)", entry.PlayerName, entry.TeamName)
+	case model.EventTypeFoul:
+		return fmt.Sprintf("Falta cometida por %s (%s)", entry.PlayerName, entry.TeamName)
+	case model.EventTypeSubstitution:
+		return fmt.Sprintf("Substituição no %s: entra %s, sai %s", entry.TeamName, entry.SubInPlayerName, entry.SubOutPlayerName)
+	case model.EventTypeThrowIn:
+		return fmt.Sprintf("Lateral para o %s", entry.TeamName)
+	case model.EventTypeCorner:
+		return fmt.Sprintf("Escanteio para o %s", entry.TeamName)
+	}
+	return event.Description
+}
+
+// GetTimeline retorna os eventos da partida em ordem cronológica, com nomes
+// de jogadores e times e o placar após cada evento
+func (c *MatchEventController) GetTimeline(ctx *gin.Context) {
+	var match model.Match
+	if err := c.DB.First(&match, ctx.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Partida não encontrada"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar partida"})
+		return
+	}
+
+	var events []model.MatchEvent
+	if err := c.DB.Where("match_id = ?", match.ID).Find(&events).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar eventos"})
+		return
+	}
+	sortEvents(events)
+
+	playerIDs := make([]uint, 0, len(events))
+	for _, e := range events {
+		playerIDs = append(playerIDs, e.PlayerID, e.SubInPlayerID, e.SubOutPlayerID)
+	}
+	var players []model.Player
+	if err := c.DB.Where("id IN ?", playerIDs).Find(&players).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar jogadores"})
+		return
+	}
+	playerNames := make(map[uint]string, len(players))
+	for _, p := range players {
+		playerNames[p.ID] = p.Name
+	}
+
+	var teams []model.Team
+	if err := c.DB.Where("id IN ?", []uint{match.HomeTeamID, match.AwayTeamID}).Find(&teams).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar times"})
+		return
+	}
+	teamNames := make(map[uint]string, len(teams))
+	for _, t := range teams {
+		teamNames[t.ID] = t.Name
+	}
+
+	timeline := model.TimelineResponse{
+		MatchID:      match.ID,
+		HomeTeamID:   match.HomeTeamID,
+		HomeTeamName: teamNames[match.HomeTeamID],
+		AwayTeamID:   match.AwayTeamID,
+		AwayTeamName: teamNames[match.AwayTeamID],
+		Status:       match.Status,
+		Events:       make([]model.TimelineEntry, 0, len(events)),
+	}
+
+	home, away := 0, 0
+	for _, e := range events {
+		if e.EventType == model.EventTypeGoal && e.Period != model.PeriodPenalties {
+			if goalSide(e, match) == model.SelectionHome {
+				home++
+			} else {
+				away++
+			}
+		}
+		entry := model.TimelineEntry{
+			EventID:     e.ID,
+			Period:      e.Period,
+			Minute:      e.Minute,
+			AddedMinute: e.AddedMinute,
+			Clock:       eventClock(e),
+			EventType:   e.EventType,
+			TeamID:      e.TeamID,
+			TeamName:    teamNames[e.TeamID],
+			PlayerID:    e.PlayerID,
+			PlayerName:  playerNames[e.PlayerID],
+			HomeScore:   home,
+			AwayScore:   away,
+		}
+		if e.EventType == model.EventTypeSubstitution {
+			entry.SubInPlayerID = e.SubInPlayerID
+			entry.SubInPlayerName = playerNames[e.SubInPlayerID]
+			entry.SubOutPlayerID = e.SubOutPlayerID
+			entry.SubOutPlayerName = playerNames[e.SubOutPlayerID]
+		}
+		entry.Text = timelineText(entry, e)
+		timeline.Events = append(timeline.Events, entry)
+	}
+
+	ctx.JSON(http.StatusOK, timeline)
+}
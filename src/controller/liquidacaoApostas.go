@@ -0,0 +1,443 @@
+package controller
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// matchResult retorna o resultado da partida do ponto de vista do mandante
+func matchResult(match model.Match) string {
+	switch {
+	case match.HomeScore > match.AwayScore:
+		return "win"
+	case match.HomeScore < match.AwayScore:
+		return "loss"
+	default:
+		return "draw"
+	}
+}
+
+// betOutcome decide a situação de uma aposta (won, lost, half_won,
+// half_lost ou push) a partir do placar final, dos eventos da partida, já em
+// ordem cronológica, e das estatísticas, nil quando não informadas. O segundo
+// retorno é falso quando a aposta não pode ser decidida automaticamente e
+// precisa ser liquidada manualmente.
+func betOutcome(bet model.Bet, match model.Match, events []model.MatchEvent, stats *model.MatchStatistics) (string, bool) {
+	switch bet.BetType {
+	case model.BetTypeWin:
+		return wonOrLost(match.HomeScore > match.AwayScore), true
+	case model.BetTypeDraw:
+		return wonOrLost(match.HomeScore == match.AwayScore), true
+	case model.BetTypeLoss:
+		return wonOrLost(match.HomeScore < match.AwayScore), true
+	case model.BetTypeBothTeamsScore:
+		return wonOrLost(match.HomeScore > 0 && match.AwayScore > 0), true
+	case model.BetTypeExactScore:
+		if bet.Selection == model.SelectionOtherScore {
+			return wonOrLost(match.HomeScore > maxExactScoreGoals || match.AwayScore > maxExactScoreGoals), true
+		}
+		home, away, ok := parseExactScore(bet.Selection)
+		if !ok {
+			return "", false
+		}
+		return wonOrLost(match.HomeScore == home && match.AwayScore == away), true
+	case model.BetTypeFirstGoal:
+		if bet.Selection == "" {
+			return "", false
+		}
+		return wonOrLost(firstGoalSide(events, match) == bet.Selection), true
+	case model.BetTypeHalfTimeResult:
+		if bet.Selection == "" {
+			return "", false
+		}
+		home, away := halfTimeScore(events, match)
+		result := model.SelectionDraw
+		if home > away {
+			result = model.SelectionHome
+		} else if away > home {
+			result = model.SelectionAway
+		}
+		return wonOrLost(result == bet.Selection), true
+	case model.BetTypeOverUnder, model.BetTypeTotalGoals:
+		return totalOutcome(bet, match.HomeScore+match.AwayScore)
+	case model.BetTypeCorners:
+		if stats == nil {
+			return "", false
+		}
+		return totalOutcome(bet, stats.HomeCorners+stats.AwayCorners)
+	case model.BetTypeCards:
+		if stats == nil {
+			return "", false
+		}
+		return totalOutcome(bet, stats.HomeYellowCards+stats.AwayYellowCards+stats.HomeRedCards+stats.AwayRedCards)
+	case model.BetTypeAsianHandicap:
+		if bet.Line == nil {
+			return "", false
+		}
+		// O handicap é somado ao time escolhido
+		diff := match.HomeScore - match.AwayScore
+		switch bet.Selection {
+		case model.SelectionHome:
+		case model.SelectionAway:
+			diff = -diff
+		default:
+			return "", false
+		}
+		return lineOutcome(float64(diff) + *bet.Line), true
+	case model.BetTypeBetBuilder:
+		// Todos os palpites da combinação precisam acertar
+		if len(bet.Selections) == 0 {
+			return "", false
+		}
+		for _, s := range bet.Selections {
+			status, ok := betOutcome(builderLeg(s), match, events, stats)
+			if !ok {
+				return "", false
+			}
+			if status != model.BetStatusWon {
+				return model.BetStatusLost, true
+			}
+		}
+		return model.BetStatusWon, true
+	}
+	return "", false
+}
+
+// wonOrLost converte o acerto do palpite na situação da aposta
+func wonOrLost(won bool) string {
+	if won {
+		return model.BetStatusWon
+	}
+	return model.BetStatusLost
+}
+
+// totalOutcome liquida um mercado de mais/menos (over/under) sobre o total informado
+func totalOutcome(bet model.Bet, total int) (string, bool) {
+	if bet.Line == nil {
+		return "", false
+	}
+	switch bet.Selection {
+	case model.SelectionOver:
+		return lineOutcome(float64(total) - *bet.Line), true
+	case model.SelectionUnder:
+		return lineOutcome(*bet.Line - float64(total)), true
+	}
+	return "", false
+}
+
+// lineOutcome liquida um mercado de linha a partir da margem a favor do
+// apostador, já descontada a linha. Em linhas inteiras a margem zero devolve
+// a aposta (push). Linhas de quarto de gol dividem a aposta em duas metades,
+// nas linhas vizinhas (2.25 vira metade em 2 e metade em 2.5), o que gera os
+// resultados de meio ganho e meia perda.
+func lineOutcome(margin float64) string {
+	if math.Mod(math.Abs(margin)*4, 2) != 1 {
+		switch {
+		case margin > 0:
+			return model.BetStatusWon
+		case margin < 0:
+			return model.BetStatusLost
+		}
+		return model.BetStatusPush
+	}
+
+	switch lineScore(margin-0.25) + lineScore(margin+0.25) {
+	case 2:
+		return model.BetStatusWon
+	case 1:
+		return model.BetStatusHalfWon
+	case 0:
+		return model.BetStatusPush
+	case -1:
+		return model.BetStatusHalfLost
+	}
+	return model.BetStatusLost
+}
+
+// lineScore é o resultado de uma metade da aposta: 1 ganha, 0 devolvida e -1 perdida
+func lineScore(margin float64) int {
+	switch {
+	case margin > 0:
+		return 1
+	case margin < 0:
+		return -1
+	}
+	return 0
+}
+
+// betPayout é o valor creditado ao apostador na situação informada. A parte
+// devolvida do valor apostado não inclui o bônus aplicado, como na anulação.
+func betPayout(bet model.Bet, status string) float64 {
+	refundable := math.Max(bet.Amount-bet.BonusApplied, 0)
+	var payout float64
+	switch status {
+	case model.BetStatusWon:
+		payout = bet.Amount * bet.Odds
+	case model.BetStatusHalfWon:
+		payout = bet.Amount/2*bet.Odds + refundable/2
+	case model.BetStatusPush:
+		payout = refundable
+	case model.BetStatusHalfLost:
+		payout = refundable / 2
+	}
+	return math.Round(payout*100) / 100
+}
+
+// payoutLedgerType é o tipo do lançamento do valor creditado: prêmio quando
+// há ganho e estorno quando só parte da aposta é devolvida
+func payoutLedgerType(status string) string {
+	if status == model.BetStatusWon || status == model.BetStatusHalfWon {
+		return model.LedgerTypeBetPayout
+	}
+	return model.LedgerTypeBetRefund
+}
+
+// settledStatuses são as situações das apostas liquidadas pelo resultado da partida
+var settledStatuses = []string{
+	model.BetStatusWon,
+	model.BetStatusLost,
+	model.BetStatusHalfWon,
+	model.BetStatusHalfLost,
+	model.BetStatusPush,
+}
+
+// settlementData carrega os gols e as estatísticas usados na liquidação
+func settlementData(tx *gorm.DB, matchID uint) ([]model.MatchEvent, *model.MatchStatistics, error) {
+	// Os mercados de primeiro gol e de intervalo dependem dos gols na ordem em que saíram
+	var events []model.MatchEvent
+	if err := tx.Where("match_id = ? AND event_type = ?", matchID, model.EventTypeGoal).Find(&events).Error; err != nil {
+		return nil, nil, err
+	}
+	sortEvents(events)
+
+	// Escanteios e cartões vêm das estatísticas, que podem ainda não ter sido lançadas
+	var stats model.MatchStatistics
+	err := tx.Where("match_id = ?", matchID).First(&stats).Error
+	if err == gorm.ErrRecordNotFound {
+		return events, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return events, &stats, nil
+}
+
+// settleMatchBets liquida as apostas pendentes de uma partida encerrada,
+// creditando o prêmio no saldo dos vencedores. Retorna quantas apostas
+// foram liquidadas.
+func settleMatchBets(tx *gorm.DB, match model.Match) (int, error) {
+	var bets []model.Bet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Selections").
+		Where("match_id = ? AND status = ?", match.ID, model.BetStatusPending).Find(&bets).Error; err != nil {
+		return 0, err
+	}
+
+	events, stats, err := settlementData(tx, match.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Os palpites de apostas de sistema são liquidados com as mesmas regras
+	if err := settleSystemBetLegs(tx, match, events, stats, ""); err != nil {
+		return 0, err
+	}
+
+	if len(bets) == 0 {
+		return 0, nil
+	}
+
+	settled := 0
+	result := matchResult(match)
+	now := time.Now()
+	for _, bet := range bets {
+		status, ok := betOutcome(bet, match, events, stats)
+		if !ok {
+			continue
+		}
+
+		bet.Result = result
+		bet.Status = status
+		bet.SettledAt = &now
+		bet.Payout = betPayout(bet, status)
+		if bet.Payout > 0 {
+			betID := bet.ID
+			if err := adjustBalance(tx, bet.UserID, &betID, payoutLedgerType(status), bet.Payout,
+				fmt.Sprintf("Liquidação da aposta #%d (%s)", bet.ID, status)); err != nil {
+				return settled, err
+			}
+		}
+
+		if err := tx.Omit(clause.Associations).Save(&bet).Error; err != nil {
+			return settled, err
+		}
+		settled++
+	}
+
+	// As apostas liquidadas deixam de compor a exposição da partida
+	if err := refreshRiskPositions(tx, match.ID); err != nil {
+		return settled, err
+	}
+	return settled, nil
+}
+
+// voidMatchBets anula em lote as apostas pendentes de uma partida. O valor
+// apostado volta ao saldo de cada usuário, exceto a parte coberta por bônus,
+// que é estornada. Cada usuário afetado recebe uma notificação. Retorna
+// quantas apostas foram anuladas.
+func voidMatchBets(tx *gorm.DB, match model.Match, reason string) (int, error) {
+	var bets []model.Bet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("match_id = ? AND status = ?", match.ID, model.BetStatusPending).
+		Find(&bets).Error; err != nil {
+		return 0, err
+	}
+	if err := voidSystemBetLegs(tx, match, reason); err != nil {
+		return 0, err
+	}
+	if len(bets) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uint, 0, len(bets))
+	refunds := make(map[uint]float64)
+	counts := make(map[uint]int)
+	var users []uint
+	var entries []model.LedgerEntry
+	for _, bet := range bets {
+		ids = append(ids, bet.ID)
+		if _, seen := counts[bet.UserID]; !seen {
+			users = append(users, bet.UserID)
+		}
+		refund := math.Max(bet.Amount-bet.BonusApplied, 0)
+		refunds[bet.UserID] += refund
+		counts[bet.UserID]++
+		if refund > 0 {
+			betID := bet.ID
+			entries = append(entries, model.LedgerEntry{
+				UserID:      bet.UserID,
+				BetID:       &betID,
+				Type:        model.LedgerTypeBetRefund,
+				Amount:      refund,
+				Description: fmt.Sprintf("Estorno da aposta #%d anulada (%s)", bet.ID, reason),
+			})
+		}
+	}
+
+	now := time.Now()
+	if err := tx.Model(&model.Bet{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"status":     model.BetStatusVoid,
+		"payout":     gorm.Expr("GREATEST(amount - bonus_applied, 0)"),
+		"settled_at": now,
+		"updated_at": now,
+	}).Error; err != nil {
+		return 0, err
+	}
+
+	notifications := make([]model.Notification, 0, len(users))
+	for _, userID := range users {
+		if refunds[userID] > 0 {
+			if err := tx.Model(&model.User{}).Where("id = ?", userID).
+				UpdateColumn("balance", gorm.Expr("balance + ?", refunds[userID])).Error; err != nil {
+				return 0, err
+			}
+		}
+		notifications = append(notifications, model.Notification{
+			UserID: userID,
+			Type:   model.NotificationTypeBetVoided,
+			Title:  "Aposta anulada",
+			Message: fmt.Sprintf("%d aposta(s) na partida #%d foram anuladas (%s). R$ %.2f foram devolvidos ao seu saldo.",
+				counts[userID], match.ID, reason, refunds[userID]),
+		})
+	}
+
+	if err := tx.CreateInBatches(&notifications, 100).Error; err != nil {
+		return 0, err
+	}
+	if len(entries) > 0 {
+		if err := tx.CreateInBatches(&entries, 100).Error; err != nil {
+			return 0, err
+		}
+	}
+	if err := refreshRiskPositions(tx, match.ID); err != nil {
+		return 0, err
+	}
+
+	return len(bets), nil
+}
+
+// resettleMatchBets reliquida as apostas já liquidadas de uma partida depois
+// de uma correção no placar ou nos eventos. A diferença de prêmio de cada
+// aposta que muda de situação é lançada como correção no saldo do usuário.
+// Retorna quantas apostas mudaram de situação.
+func resettleMatchBets(tx *gorm.DB, match model.Match, reason string) (int, error) {
+	var bets []model.Bet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Selections").
+		Where("match_id = ? AND status IN ? AND is_cashout = ? AND settled_manually = ?", match.ID,
+			settledStatuses, false, false).
+		Find(&bets).Error; err != nil {
+		return 0, err
+	}
+
+	events, stats, err := settlementData(tx, match.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := settleSystemBetLegs(tx, match, events, stats, reason); err != nil {
+		return 0, err
+	}
+
+	if len(bets) == 0 {
+		return 0, nil
+	}
+
+	resettled := 0
+	result := matchResult(match)
+	var notifications []model.Notification
+	for _, bet := range bets {
+		status, ok := betOutcome(bet, match, events, stats)
+		if !ok {
+			continue
+		}
+		payout := betPayout(bet, status)
+		if status == bet.Status && result == bet.Result {
+			continue
+		}
+
+		if status != bet.Status {
+			delta := payout - bet.Payout
+			betID := bet.ID
+			if err := adjustBalance(tx, bet.UserID, &betID, model.LedgerTypeCorrection, delta,
+				fmt.Sprintf("Reliquidação da aposta #%d: %s", bet.ID, reason)); err != nil {
+				return resettled, err
+			}
+			notifications = append(notifications, model.Notification{
+				UserID: bet.UserID,
+				Type:   model.NotificationTypeBetSettled,
+				Title:  "Aposta reliquidada",
+				Message: fmt.Sprintf("Sua aposta #%d na partida #%d foi reliquidada após correção dos dados da partida. Ajuste no saldo: R$ %.2f.",
+					bet.ID, match.ID, delta),
+			})
+			resettled++
+		}
+
+		bet.Status = status
+		bet.Payout = payout
+		bet.Result = result
+		if err := tx.Omit(clause.Associations).Save(&bet).Error; err != nil {
+			return resettled, err
+		}
+	}
+
+	if len(notifications) > 0 {
+		if err := tx.CreateInBatches(&notifications, 100).Error; err != nil {
+			return resettled, err
+		}
+	}
+	return resettled, nil
+}
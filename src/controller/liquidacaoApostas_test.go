@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+)
+
+func TestLineOutcome(t *testing.T) {
+	tests := []struct {
+		name   string
+		margin float64
+		want   string
+	}{
+		{"linha inteira vencida", 1, model.BetStatusWon},
+		{"linha inteira perdida", -1, model.BetStatusLost},
+		{"linha inteira empatada devolve", 0, model.BetStatusPush},
+		{"meia linha vencida", 0.5, model.BetStatusWon},
+		{"meia linha perdida", -0.5, model.BetStatusLost},
+		{"quarto vencido nas duas metades", 0.75, model.BetStatusWon},
+		{"quarto com meio ganho", 0.25, model.BetStatusHalfWon},
+		{"quarto com meia perda", -0.25, model.BetStatusHalfLost},
+		{"quarto perdido nas duas metades", -0.75, model.BetStatusLost},
+		{"quarto com margem maior", 1.25, model.BetStatusWon},
+		{"quarto com derrota maior", -1.25, model.BetStatusLost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineOutcome(tt.margin); got != tt.want {
+				t.Errorf("lineOutcome(%v) = %s, esperado %s", tt.margin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBetPayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		bet    model.Bet
+		status string
+		want   float64
+	}{
+		{"ganha", model.Bet{Amount: 100, Odds: 1.9}, model.BetStatusWon, 190},
+		{"perdida", model.Bet{Amount: 100, Odds: 1.9}, model.BetStatusLost, 0},
+		{"devolvida", model.Bet{Amount: 100, Odds: 1.9}, model.BetStatusPush, 100},
+		{"meio ganho", model.Bet{Amount: 100, Odds: 1.9}, model.BetStatusHalfWon, 145},
+		{"meia perda", model.Bet{Amount: 100, Odds: 1.9}, model.BetStatusHalfLost, 50},
+		{"ganha com bônus", model.Bet{Amount: 100, Odds: 1.9, BonusApplied: 20}, model.BetStatusWon, 190},
+		{"devolvida sem o bônus", model.Bet{Amount: 100, Odds: 1.9, BonusApplied: 20}, model.BetStatusPush, 80},
+		{"meio ganho sem o bônus na metade devolvida", model.Bet{Amount: 100, Odds: 1.9, BonusApplied: 20}, model.BetStatusHalfWon, 135},
+		{"meia perda sem o bônus", model.Bet{Amount: 100, Odds: 1.9, BonusApplied: 20}, model.BetStatusHalfLost, 40},
+		{"bônus maior que a aposta não devolve nada", model.Bet{Amount: 10, Odds: 2, BonusApplied: 15}, model.BetStatusPush, 0},
+		{"arredonda em centavos", model.Bet{Amount: 10.33, Odds: 1.87}, model.BetStatusHalfWon, 14.82},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := betPayout(tt.bet, tt.status); got != tt.want {
+				t.Errorf("betPayout(%s) = %v, esperado %v", tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExactScoreOutcome(t *testing.T) {
+	tests := []struct {
+		name       string
+		selection  string
+		home, away int
+		want       string
+		wantOK     bool
+	}{
+		{"placar certo", "2-1", 2, 1, model.BetStatusWon, true},
+		{"placar invertido", "2-1", 1, 2, model.BetStatusLost, true},
+		{"empate sem gols", "0-0", 0, 0, model.BetStatusWon, true},
+		{"outro placar acima do limite", model.SelectionOtherScore, 5, 0, model.BetStatusWon, true},
+		{"outro placar dentro do limite", model.SelectionOtherScore, 4, 4, model.BetStatusLost, true},
+		{"palpite inválido vai para liquidação manual", "2x1", 2, 1, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bet := model.Bet{BetType: model.BetTypeExactScore, Selection: tt.selection}
+			match := model.Match{HomeScore: tt.home, AwayScore: tt.away}
+			got, ok := betOutcome(bet, match, nil, nil)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("betOutcome(%s, %d x %d) = %s, %v; esperado %s, %v", tt.selection, tt.home, tt.away, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"gorm.io/gorm"
+)
+
+type NotificationController struct {
+	DB *gorm.DB
+}
+
+func NewNotificationController(db *gorm.DB) *NotificationController {
+	return &NotificationController{DB: db}
+}
+
+// ListNotifications retorna as notificações do usuário autenticado
+func (c *NotificationController) ListNotifications(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	offset := (page - 1) * limit
+
+	query := c.DB.Model(&model.Notification{}).Where("user_id = ?", userID)
+	if unread := ctx.Query("unread"); unread == "true" {
+		query = query.Where("is_read = ?", false)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var notifications []model.Notification
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&notifications).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar notificações"})
+		return
+	}
+
+	response := make([]model.NotificationResponse, 0, len(notifications))
+	for _, n := range notifications {
+		response = append(response, model.NotificationResponse{
+			ID:        n.ID,
+			Type:      n.Type,
+			Title:     n.Title,
+			Message:   n.Message,
+			IsRead:    n.IsRead,
+			CreatedAt: n.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": response,
+		"meta": gin.H{
+			"total":  total,
+			"page":   page,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// MarkAsRead marca uma notificação do usuário como lida
+func (c *NotificationController) MarkAsRead(ctx *gin.Context) {
+	userID := ctx.GetUint("user_id")
+
+	var notification model.Notification
+	if err := c.DB.Where("id = ? AND user_id = ?", ctx.Param("id"), userID).First(&notification).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Notificação não encontrada"})
+		return
+	}
+
+	notification.IsRead = true
+	if err := c.DB.Save(&notification).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar notificação"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Notificação marcada como lida"})
+}
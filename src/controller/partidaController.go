@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/lfdelima3/Backend-Go-Bet/src/config"
 	"github.com/lfdelima3/Backend-Go-Bet/src/model"
 	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
 )
 
 // CreateMatch cria uma nova partida
@@ -55,6 +57,17 @@ func CreateMatch(c *gin.Context) {
 		return
 	}
 
+	// Os dois times precisam estar inscritos no torneio
+	registered, err := teamsRegistered(config.DB, tournament.ID, matchCreate.HomeTeamID, matchCreate.AwayTeamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar inscrição dos times"})
+		return
+	}
+	if !registered {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Os dois times precisam estar inscritos no torneio"})
+		return
+	}
+
 	// Verifica se já existe partida no mesmo horário para algum dos times
 	var existingMatch model.Match
 	if err := config.DB.Where(
@@ -73,7 +86,8 @@ func CreateMatch(c *gin.Context) {
 		AwayTeamID:   matchCreate.AwayTeamID,
 		StartTime:    matchCreate.StartTime,
 		EndTime:      matchCreate.EndTime,
-		Status:       "scheduled",
+		Status:       model.MatchStatusScheduled,
+		MarketStatus: model.MarketStatusOpen,
 		Stadium:      matchCreate.Stadium,
 		Referee:      matchCreate.Referee,
 		Weather:      matchCreate.Weather,
@@ -84,23 +98,7 @@ func CreateMatch(c *gin.Context) {
 		return
 	}
 
-	response := model.MatchResponse{
-		ID:           match.ID,
-		TournamentID: match.TournamentID,
-		HomeTeamID:   match.HomeTeamID,
-		AwayTeamID:   match.AwayTeamID,
-		StartTime:    match.StartTime,
-		EndTime:      match.EndTime,
-		Status:       match.Status,
-		HomeScore:    match.HomeScore,
-		AwayScore:    match.AwayScore,
-		Stadium:      match.Stadium,
-		Referee:      match.Referee,
-		Attendance:   match.Attendance,
-		Weather:      match.Weather,
-		CreatedAt:    match.CreatedAt,
-		UpdatedAt:    match.UpdatedAt,
-	}
+	response := toMatchResponse(match)
 
 	c.JSON(http.StatusCreated, response)
 }
@@ -156,23 +154,7 @@ func ListMatches(c *gin.Context) {
 	// Converte para response
 	var response []model.MatchResponse
 	for _, match := range matches {
-		response = append(response, model.MatchResponse{
-			ID:           match.ID,
-			TournamentID: match.TournamentID,
-			HomeTeamID:   match.HomeTeamID,
-			AwayTeamID:   match.AwayTeamID,
-			StartTime:    match.StartTime,
-			EndTime:      match.EndTime,
-			Status:       match.Status,
-			HomeScore:    match.HomeScore,
-			AwayScore:    match.AwayScore,
-			Stadium:      match.Stadium,
-			Referee:      match.Referee,
-			Attendance:   match.Attendance,
-			Weather:      match.Weather,
-			CreatedAt:    match.CreatedAt,
-			UpdatedAt:    match.UpdatedAt,
-		})
+		response = append(response, toMatchResponse(match))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -196,23 +178,7 @@ func GetMatch(c *gin.Context) {
 		return
 	}
 
-	response := model.MatchResponse{
-		ID:           match.ID,
-		TournamentID: match.TournamentID,
-		HomeTeamID:   match.HomeTeamID,
-		AwayTeamID:   match.AwayTeamID,
-		StartTime:    match.StartTime,
-		EndTime:      match.EndTime,
-		Status:       match.Status,
-		HomeScore:    match.HomeScore,
-		AwayScore:    match.AwayScore,
-		Stadium:      match.Stadium,
-		Referee:      match.Referee,
-		Attendance:   match.Attendance,
-		Weather:      match.Weather,
-		CreatedAt:    match.CreatedAt,
-		UpdatedAt:    match.UpdatedAt,
-	}
+	response := toMatchResponse(match)
 
 	c.JSON(http.StatusOK, response)
 }
@@ -238,6 +204,33 @@ func UpdateMatch(c *gin.Context) {
 		return
 	}
 
+	// O status só muda pelos endpoints de transição
+	if update.Status != "" && update.Status != match.Status {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "O status da partida deve ser alterado pelos endpoints de transição"})
+		return
+	}
+
+	// Torneio, times e horários só podem mudar antes do início da partida
+	editable := match.Status == model.MatchStatusScheduled || match.Status == model.MatchStatusPostponed
+	if !editable && (update.TournamentID != 0 || update.HomeTeamID != 0 || update.AwayTeamID != 0 ||
+		!update.StartTime.IsZero() || !update.EndTime.IsZero()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Torneio, times e horários não podem ser alterados depois que a partida começou"})
+		return
+	}
+
+	// Nas partidas do mata-mata, os times são definidos pelo chaveamento
+	if match.BracketTieID != nil && (update.TournamentID != 0 || update.HomeTeamID != 0 || update.AwayTeamID != 0) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Torneio e times de partidas do mata-mata são definidos pelo chaveamento"})
+		return
+	}
+
+	// O placar só pode ser alterado com a partida em andamento
+	inPlay := match.Status == model.MatchStatusLive || match.Status == model.MatchStatusHalfTime
+	if !inPlay && (update.HomeScore != nil || update.AwayScore != nil) {
+		c.JSON(http.StatusConflict, gin.H{"error": "O placar só pode ser alterado com a partida em andamento"})
+		return
+	}
+
 	// Validação de datas
 	if !update.StartTime.IsZero() && !update.EndTime.IsZero() && update.StartTime.After(update.EndTime) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Data de início deve ser anterior à data de término"})
@@ -303,20 +296,30 @@ func UpdateMatch(c *gin.Context) {
 		}
 	}
 
+	// Com torneio ou times alterados, os dois times precisam continuar inscritos
+	if update.TournamentID != 0 || update.HomeTeamID != 0 || update.AwayTeamID != 0 {
+		registered, err := teamsRegistered(config.DB, match.TournamentID, match.HomeTeamID, match.AwayTeamID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar inscrição dos times"})
+			return
+		}
+		if !registered {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Os dois times precisam estar inscritos no torneio"})
+			return
+		}
+	}
+
 	if !update.StartTime.IsZero() {
 		match.StartTime = update.StartTime
 	}
 	if !update.EndTime.IsZero() {
 		match.EndTime = update.EndTime
 	}
-	if update.Status != "" {
-		match.Status = update.Status
-	}
-	if update.HomeScore >= 0 {
-		match.HomeScore = update.HomeScore
+	if update.HomeScore != nil {
+		match.HomeScore = *update.HomeScore
 	}
-	if update.AwayScore >= 0 {
-		match.AwayScore = update.AwayScore
+	if update.AwayScore != nil {
+		match.AwayScore = *update.AwayScore
 	}
 	if update.Stadium != "" {
 		match.Stadium = update.Stadium
@@ -336,23 +339,7 @@ func UpdateMatch(c *gin.Context) {
 		return
 	}
 
-	response := model.MatchResponse{
-		ID:           match.ID,
-		TournamentID: match.TournamentID,
-		HomeTeamID:   match.HomeTeamID,
-		AwayTeamID:   match.AwayTeamID,
-		StartTime:    match.StartTime,
-		EndTime:      match.EndTime,
-		Status:       match.Status,
-		HomeScore:    match.HomeScore,
-		AwayScore:    match.AwayScore,
-		Stadium:      match.Stadium,
-		Referee:      match.Referee,
-		Attendance:   match.Attendance,
-		Weather:      match.Weather,
-		CreatedAt:    match.CreatedAt,
-		UpdatedAt:    match.UpdatedAt,
-	}
+	response := toMatchResponse(match)
 
 	c.JSON(http.StatusOK, response)
 }
@@ -367,17 +354,66 @@ func DeleteMatch(c *gin.Context) {
 	}
 
 	// Verifica se a partida já começou
-	if match.Status == "live" || match.Status == "finished" {
+	if match.Status != model.MatchStatusScheduled && match.Status != model.MatchStatusPostponed {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Não é possível cancelar uma partida que já começou ou terminou"})
 		return
 	}
 
-	// Soft delete - apenas marca como cancelada
-	match.Status = "cancelled"
-	if err := config.DB.Save(&match).Error; err != nil {
+	// Soft delete - marca como cancelada e anula as apostas pendentes. A partida
+	// é relida com bloqueio para não cancelar uma partida iniciada nesse meio tempo
+	voided := 0
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockMatch(tx, &match); err != nil {
+			return err
+		}
+		if match.Status != model.MatchStatusScheduled && match.Status != model.MatchStatusPostponed {
+			return errStaleTransition
+		}
+		var err error
+		voided, err = closeMatchWithoutResult(tx, &match, model.MatchStatusCancelled, "partida cancelada")
+		if err != nil {
+			return err
+		}
+		return tx.Save(&match).Error
+	})
+	if err != nil {
+		if errors.Is(err, errStaleTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Não é possível cancelar uma partida que já começou ou terminou"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao cancelar partida", "details": err.Error()})
 		return
 	}
+	invalidateMatchAnalytics(config.DB, match.ID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Partida cancelada com sucesso", "bets_voided": voided})
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Partida cancelada com sucesso"})
+func toMatchResponse(match model.Match) model.MatchResponse {
+	return model.MatchResponse{
+		ID:                 match.ID,
+		TournamentID:       match.TournamentID,
+		Round:              match.Round,
+		BracketTieID:       match.BracketTieID,
+		Leg:                match.Leg,
+		HomeTeamID:         match.HomeTeamID,
+		AwayTeamID:         match.AwayTeamID,
+		StartTime:          match.StartTime,
+		EndTime:            match.EndTime,
+		Status:             match.Status,
+		StatusReason:       match.StatusReason,
+		MarketStatus:       match.MarketStatus,
+		HomeScore:          match.HomeScore,
+		AwayScore:          match.AwayScore,
+		HomeExtraTimeScore: match.HomeExtraTimeScore,
+		AwayExtraTimeScore: match.AwayExtraTimeScore,
+		HomePenalties:      match.HomePenalties,
+		AwayPenalties:      match.AwayPenalties,
+		Stadium:            match.Stadium,
+		Referee:            match.Referee,
+		Attendance:         match.Attendance,
+		Weather:            match.Weather,
+		CreatedAt:          match.CreatedAt,
+		UpdatedAt:          match.UpdatedAt,
+	}
 }
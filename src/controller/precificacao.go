@@ -0,0 +1,278 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"gorm.io/gorm"
+)
+
+// Parâmetros do modelo de precificação (Poisson com correção de Dixon–Coles)
+const (
+	// ratingLookback limita o histórico usado nas forças dos times
+	ratingLookback = 2 * 365 * 24 * time.Hour
+	// ratingHalfLife é a meia-vida do peso de uma partida: resultados recentes pesam mais
+	ratingHalfLife = 180 * 24 * time.Hour
+	// ratingPriorMatches aproxima as forças da média quando há poucas partidas
+	ratingPriorMatches = 3.0
+	// dixonColesRho corrige a frequência dos placares baixos (0-0, 1-0, 0-1 e 1-1)
+	dixonColesRho = -0.08
+	// maxModelGoals é o maior número de gols por time considerado na matriz de placares
+	maxModelGoals = 10
+	// maxExactScoreGoals é o maior número de gols por time nos placares exatos oferecidos
+	maxExactScoreGoals = 4
+)
+
+// totalGoalsLines são as linhas oferecidas no mercado de total de gols
+var totalGoalsLines = []float64{1.5, 2.5, 3.5}
+
+// asianHandicapLines são as linhas de handicap do mandante oferecidas. Só
+// linhas de meio gol são precificadas, pois não têm devolução (push).
+var asianHandicapLines = []float64{-1.5, -0.5, 0.5, 1.5}
+
+// errNoHistory indica que não há partidas encerradas para calcular as forças
+var errNoHistory = errors.New("não há partidas encerradas suficientes para precificar")
+
+// teamRating acumula gols marcados e sofridos ponderados pelo tempo
+type teamRating struct {
+	GoalsFor     float64
+	GoalsAgainst float64
+	Weight       float64
+	Matches      int
+}
+
+// ratingModel reúne as médias da base e as forças de cada time
+type ratingModel struct {
+	HomeAverage float64
+	AwayAverage float64
+	Teams       map[uint]*teamRating
+	MatchesUsed int
+}
+
+// buildRatings calcula as médias de gols e os acumulados de cada time a
+// partir das partidas encerradas, considerando apenas o tempo regulamentar
+func buildRatings(matches []model.Match, now time.Time) (ratingModel, error) {
+	r := ratingModel{Teams: make(map[uint]*teamRating)}
+	var homeGoals, awayGoals, totalWeight float64
+	for _, m := range matches {
+		age := now.Sub(m.StartTime)
+		if age < 0 || age > ratingLookback {
+			continue
+		}
+		w := math.Pow(0.5, float64(age)/float64(ratingHalfLife))
+		homeGoals += w * float64(m.HomeScore)
+		awayGoals += w * float64(m.AwayScore)
+		totalWeight += w
+		r.MatchesUsed++
+
+		for _, side := range []struct {
+			team            uint
+			scored, allowed int
+		}{{m.HomeTeamID, m.HomeScore, m.AwayScore}, {m.AwayTeamID, m.AwayScore, m.HomeScore}} {
+			t, ok := r.Teams[side.team]
+			if !ok {
+				t = &teamRating{}
+				r.Teams[side.team] = t
+			}
+			t.GoalsFor += w * float64(side.scored)
+			t.GoalsAgainst += w * float64(side.allowed)
+			t.Weight += w
+			t.Matches++
+		}
+	}
+	if totalWeight == 0 {
+		return r, errNoHistory
+	}
+
+	r.HomeAverage = homeGoals / totalWeight
+	r.AwayAverage = awayGoals / totalWeight
+	if r.HomeAverage == 0 || r.AwayAverage == 0 {
+		return r, errNoHistory
+	}
+	return r, nil
+}
+
+// loadRatings busca o histórico recente de partidas encerradas e calcula as
+// forças dos times para precificar a partida informada
+func loadRatings(db *gorm.DB, match model.Match, now time.Time) (ratingModel, error) {
+	var history []model.Match
+	if err := db.Where("status = ? AND start_time >= ? AND id <> ?",
+		model.MatchStatusFinished, now.Add(-ratingLookback), match.ID).
+		Find(&history).Error; err != nil {
+		return ratingModel{}, err
+	}
+	return buildRatings(history, now)
+}
+
+// strength retorna as forças de ataque e defesa de um time, aproximadas da
+// média (1) quando o time tem poucas partidas na base
+func (r ratingModel) strength(teamID uint) (attack, defence float64, matches int) {
+	average := (r.HomeAverage + r.AwayAverage) / 2
+	t, ok := r.Teams[teamID]
+	if !ok {
+		return 1, 1, 0
+	}
+	attack = (t.GoalsFor + ratingPriorMatches*average) / ((t.Weight + ratingPriorMatches) * average)
+	defence = (t.GoalsAgainst + ratingPriorMatches*average) / ((t.Weight + ratingPriorMatches) * average)
+	return attack, defence, t.Matches
+}
+
+// expectedGoals retorna a média de gols esperada de cada time na partida
+func (r ratingModel) expectedGoals(homeID, awayID uint) (float64, float64) {
+	homeAttack, homeDefence, _ := r.strength(homeID)
+	awayAttack, awayDefence, _ := r.strength(awayID)
+	return r.HomeAverage * homeAttack * awayDefence, r.AwayAverage * awayAttack * homeDefence
+}
+
+// poissonPMF é a probabilidade de exatamente k gols com média lambda
+func poissonPMF(k int, lambda float64) float64 {
+	lg, _ := math.Lgamma(float64(k + 1))
+	return math.Exp(float64(k)*math.Log(lambda) - lambda - lg)
+}
+
+// dixonColesTau é o fator de correção de Dixon–Coles para placares baixos
+func dixonColesTau(home, away int, lambdaHome, lambdaAway, rho float64) float64 {
+	switch {
+	case home == 0 && away == 0:
+		return 1 - lambdaHome*lambdaAway*rho
+	case home == 0 && away == 1:
+		return 1 + lambdaHome*rho
+	case home == 1 && away == 0:
+		return 1 + lambdaAway*rho
+	case home == 1 && away == 1:
+		return 1 - rho
+	}
+	return 1
+}
+
+// scoreMatrix retorna a probabilidade de cada placar, normalizada para somar 1
+func scoreMatrix(lambdaHome, lambdaAway float64) [][]float64 {
+	matrix := make([][]float64, maxModelGoals+1)
+	total := 0.0
+	for h := 0; h <= maxModelGoals; h++ {
+		matrix[h] = make([]float64, maxModelGoals+1)
+		for a := 0; a <= maxModelGoals; a++ {
+			p := poissonPMF(h, lambdaHome) * poissonPMF(a, lambdaAway) *
+				dixonColesTau(h, a, lambdaHome, lambdaAway, dixonColesRho)
+			if p < 0 {
+				p = 0
+			}
+			matrix[h][a] = p
+			total += p
+		}
+	}
+	for h := range matrix {
+		for a := range matrix[h] {
+			matrix[h][a] /= total
+		}
+	}
+	return matrix
+}
+
+// pricedSelection é a probabilidade de uma opção de mercado
+type pricedSelection struct {
+	Market      string
+	Selection   string
+	Line        *float64
+	Probability float64
+}
+
+// marketProbabilities deriva os mercados oferecidos a partir da matriz de
+// placares. Só são publicadas as opções que uma aposta consegue referenciar e
+// que a liquidação sabe resolver: "ambos marcam: não" fica de fora até existir
+// palpite para ele.
+func marketProbabilities(matrix [][]float64) [][]pricedSelection {
+	var home, draw, away, btts float64
+	for h := range matrix {
+		for a, p := range matrix[h] {
+			switch {
+			case h > a:
+				home += p
+			case h == a:
+				draw += p
+			default:
+				away += p
+			}
+			if h > 0 && a > 0 {
+				btts += p
+			}
+		}
+	}
+
+	markets := [][]pricedSelection{
+		{
+			{Market: model.MarketMatchResult, Selection: model.SelectionHome, Probability: home},
+			{Market: model.MarketMatchResult, Selection: model.SelectionDraw, Probability: draw},
+			{Market: model.MarketMatchResult, Selection: model.SelectionAway, Probability: away},
+		},
+		{
+			{Market: model.MarketBothTeamsScore, Selection: "yes", Probability: btts},
+		},
+	}
+
+	for _, line := range totalGoalsLines {
+		line := line
+		under := 0.0
+		for h := range matrix {
+			for a, p := range matrix[h] {
+				if float64(h+a) < line {
+					under += p
+				}
+			}
+		}
+		markets = append(markets, []pricedSelection{
+			{Market: model.MarketTotalGoals, Selection: "over", Line: &line, Probability: 1 - under},
+			{Market: model.MarketTotalGoals, Selection: "under", Line: &line, Probability: under},
+		})
+	}
+
+	for _, line := range asianHandicapLines {
+		homeLine, awayLine := line, -line
+		home := 0.0
+		for h := range matrix {
+			for a, p := range matrix[h] {
+				if float64(h-a)+line > 0 {
+					home += p
+				}
+			}
+		}
+		markets = append(markets, []pricedSelection{
+			{Market: model.MarketAsianHandicap, Selection: model.SelectionHome, Line: &homeLine, Probability: home},
+			{Market: model.MarketAsianHandicap, Selection: model.SelectionAway, Line: &awayLine, Probability: 1 - home},
+		})
+	}
+
+	// Placares exatos até maxExactScoreGoals; os demais ficam em "other"
+	var exact []pricedSelection
+	other := 1.0
+	for h := 0; h <= maxExactScoreGoals; h++ {
+		for a := 0; a <= maxExactScoreGoals; a++ {
+			exact = append(exact, pricedSelection{
+				Market:      model.MarketExactScore,
+				Selection:   fmt.Sprintf("%d-%d", h, a),
+				Probability: matrix[h][a],
+			})
+			other -= matrix[h][a]
+		}
+	}
+	exact = append(exact, pricedSelection{Market: model.MarketExactScore, Selection: model.SelectionOtherScore, Probability: math.Max(other, 0)})
+	return append(markets, exact)
+}
+
+// applyMargin converte probabilidades em cotações com a margem da casa,
+// distribuída proporcionalmente entre as opções do mercado. Retorna a cotação
+// justa e a cotação oferecida, arredondadas para duas casas.
+func applyMargin(probability, margin float64) (float64, float64) {
+	if probability <= 0 {
+		return 0, 0
+	}
+	fair := 1 / probability
+	offered := fair / (1 + margin)
+	if offered < 1.01 {
+		offered = 1.01
+	}
+	return math.Round(fair*100) / 100, math.Round(offered*100) / 100
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+)
+
+func TestScoreMatrix(t *testing.T) {
+	tests := []struct {
+		lambdaHome, lambdaAway float64
+	}{
+		{1.4, 1.1},
+		{1.2, 1.2},
+		{0.2, 0.1},
+		{3.5, 2.8},
+		{6, 0.3},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%.1f x %.1f", tt.lambdaHome, tt.lambdaAway), func(t *testing.T) {
+			matrix := scoreMatrix(tt.lambdaHome, tt.lambdaAway)
+			if len(matrix) != maxModelGoals+1 {
+				t.Fatalf("%d linhas, esperado %d", len(matrix), maxModelGoals+1)
+			}
+
+			total := 0.0
+			for h := range matrix {
+				if len(matrix[h]) != maxModelGoals+1 {
+					t.Fatalf("linha %d com %d colunas, esperado %d", h, len(matrix[h]), maxModelGoals+1)
+				}
+				for a, p := range matrix[h] {
+					if p < 0 || math.IsNaN(p) {
+						t.Errorf("placar %d x %d com probabilidade %v", h, a, p)
+					}
+					total += p
+				}
+			}
+			if math.Abs(total-1) > 1e-9 {
+				t.Errorf("a matriz soma %v, esperado 1", total)
+			}
+
+			// Com médias iguais, o placar invertido tem a mesma probabilidade
+			if tt.lambdaHome == tt.lambdaAway {
+				for h := range matrix {
+					for a := range matrix[h] {
+						if math.Abs(matrix[h][a]-matrix[a][h]) > 1e-12 {
+							t.Errorf("P(%d x %d) = %v e P(%d x %d) = %v", h, a, matrix[h][a], a, h, matrix[a][h])
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMarketProbabilities(t *testing.T) {
+	markets := marketProbabilities(scoreMatrix(1.4, 1.1))
+
+	exact := 0
+	for _, options := range markets {
+		if options[0].Market == model.MarketBothTeamsScore {
+			continue
+		}
+		// As opções de cada mercado são excludentes e cobrem todos os placares
+		total := 0.0
+		for _, o := range options {
+			total += o.Probability
+			if o.Market == model.MarketExactScore {
+				exact++
+			}
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Errorf("mercado %s soma %v, esperado 1", options[0].Market, total)
+		}
+	}
+	if want := (maxExactScoreGoals+1)*(maxExactScoreGoals+1) + 1; exact != want {
+		t.Errorf("%d placares exatos, esperado %d", exact, want)
+	}
+}
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"gorm.io/gorm"
+)
+
+// reportGrouping é a chave, o nome e as junções de um agrupamento do relatório
+type reportGrouping struct {
+	Key   string
+	Label string
+	Joins string
+}
+
+// reportGroupings são os agrupamentos aceitos em group_by. As apostas de
+// sistema envolvem várias partidas e ficam sem torneio, partida e promoção.
+var reportGroupings = map[string]reportGrouping{
+	"": {Key: "''", Label: "''"},
+	"tournament": {
+		Key:   "COALESCE(CAST(m.tournament_id AS text), '')",
+		Label: "COALESCE(t.name, 'Sem torneio')",
+		Joins: "LEFT JOIN matches m ON m.id = rb.match_id LEFT JOIN tournaments t ON t.id = m.tournament_id",
+	},
+	"match": {
+		Key:   "COALESCE(CAST(rb.match_id AS text), '')",
+		Label: "COALESCE(home_team.name || ' x ' || away_team.name, 'Apostas de sistema')",
+		Joins: "LEFT JOIN matches m ON m.id = rb.match_id " +
+			"LEFT JOIN teams home_team ON home_team.id = m.home_team_id " +
+			"LEFT JOIN teams away_team ON away_team.id = m.away_team_id",
+	},
+	"bet_type": {
+		Key:   "rb.bet_type",
+		Label: "rb.bet_type",
+	},
+	"promotion": {
+		Key:   "COALESCE(CAST(rb.promotion_id AS text), '')",
+		Label: "COALESCE(p.name, 'Sem promoção')",
+		Joins: "LEFT JOIN promotions p ON p.id = rb.promotion_id",
+	},
+}
+
+// errInvalidReportFilter indica um filtro do relatório com valor inválido
+var errInvalidReportFilter = errors.New("partida inválida no filtro match_id")
+
+type ReportController struct {
+	DB *gorm.DB
+}
+
+func NewReportController(db *gorm.DB) *ReportController {
+	return &ReportController{DB: db}
+}
+
+// financialReport agrega as apostas liquidadas, pela data da primeira
+// liquidação (períodos fechados não mudam com reliquidações), no intervalo e
+// agrupamento informados. As apostas de sistema entram como o tipo "system".
+func financialReport(db *gorm.DB, interval, groupBy string, filters betFilters) ([]model.FinancialReportRow, error) {
+	grouping := reportGroupings[groupBy]
+	groupClause := "GROUP BY 1, 2, 3"
+	if groupBy == "" {
+		groupClause = "GROUP BY 1"
+	}
+
+	params := map[string]interface{}{
+		"interval": interval,
+		"settled":  settledStatuses,
+		"system":   []string{model.BetStatusWon, model.BetStatusLost},
+	}
+	period := ""
+	if filters.Start != nil {
+		period += " AND settled_at >= @start"
+		params["start"] = *filters.Start
+	}
+	if filters.End != nil {
+		period += " AND settled_at < @end"
+		params["end"] = *filters.End
+	}
+	if filters.Status != "" {
+		period += " AND status = @status"
+		params["status"] = filters.Status
+	}
+
+	// Partida e tipo de aposta só existem nas apostas simples; as de sistema
+	// entram apenas no filtro pelo tipo "system"
+	betFilter, systemFilter := period, period
+	if filters.MatchID != "" {
+		matchID, err := strconv.ParseUint(filters.MatchID, 10, 64)
+		if err != nil {
+			return nil, errInvalidReportFilter
+		}
+		betFilter += " AND match_id = @match_id"
+		systemFilter += " AND FALSE"
+		params["match_id"] = matchID
+	}
+	if filters.BetType != "" {
+		betFilter += " AND bet_type = @bet_type"
+		if filters.BetType != systemBetType {
+			systemFilter += " AND FALSE"
+		}
+		params["bet_type"] = filters.BetType
+	}
+
+	sql := `WITH rb AS (
+			SELECT settled_at, amount AS stake, payout, bonus_applied AS bonus,
+				bet_type, match_id, promotion_id
+			FROM bets WHERE status IN @settled` + betFilter + `
+			UNION ALL
+			SELECT settled_at, total_stake, payout, 0, 'system', NULL, NULL
+			FROM system_bets WHERE status IN @system` + systemFilter + `
+		)
+		SELECT date_trunc(@interval, rb.settled_at) AS period,
+			` + grouping.Key + ` AS group_key,
+			` + grouping.Label + ` AS group_label,
+			COUNT(*) AS bets,
+			COALESCE(SUM(rb.stake), 0) AS stakes,
+			COALESCE(SUM(rb.payout), 0) AS payouts,
+			COALESCE(SUM(rb.bonus), 0) AS bonus_cost
+		FROM rb ` + grouping.Joins + `
+		` + groupClause + `
+		ORDER BY 1, 2`
+
+	var rows []model.FinancialReportRow
+	if err := db.Raw(sql, params).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		r := &rows[i]
+		r.Stakes = round2(r.Stakes)
+		r.Payouts = round2(r.Payouts)
+		r.BonusCost = round2(r.BonusCost)
+		r.GGR = round2(r.Stakes - r.Payouts)
+		r.NGR = round2(r.GGR - r.BonusCost)
+		r.Hold = percent(r.GGR, r.Stakes)
+	}
+	return rows, nil
+}
+
+// GetFinancialReport retorna o volume apostado, os prêmios, o GGR, o custo
+// de bônus e o hold por dia, semana ou mês (interval), com agrupamento
+// opcional por torneio, partida, tipo de aposta ou promoção (group_by).
+// Os filtros status, match_id e bet_type restringem as apostas consideradas.
+// Com format=csv o relatório é baixado para a contabilidade.
+func (c *ReportController) GetFinancialReport(ctx *gin.Context) {
+	interval := ctx.DefaultQuery("interval", "day")
+	if interval != "day" && interval != "week" && interval != "month" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Intervalo inválido, use day, week ou month"})
+		return
+	}
+	groupBy := ctx.Query("group_by")
+	if _, ok := reportGroupings[groupBy]; !ok {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Agrupamento inválido, use tournament, match, bet_type ou promotion"})
+		return
+	}
+	format := ctx.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Formato inválido, use json ou csv"})
+		return
+	}
+	filters, err := parseBetFilters(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rows, err := financialReport(c.DB, interval, groupBy, filters)
+	if err != nil {
+		if errors.Is(err, errInvalidReportFilter) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Partida inválida no filtro match_id"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar relatório financeiro", "details": err.Error()})
+		return
+	}
+
+	response := model.FinancialReportResponse{
+		Interval:  interval,
+		GroupBy:   groupBy,
+		StartDate: filters.Start,
+		Rows:      rows,
+	}
+	if filters.End != nil {
+		end := filters.End.AddDate(0, 0, -1)
+		response.EndDate = &end
+	}
+	if response.Rows == nil {
+		response.Rows = []model.FinancialReportRow{}
+	}
+	for _, r := range rows {
+		response.Totals.Bets += r.Bets
+		response.Totals.Stakes += r.Stakes
+		response.Totals.Payouts += r.Payouts
+		response.Totals.BonusCost += r.BonusCost
+	}
+	response.Totals.Stakes = round2(response.Totals.Stakes)
+	response.Totals.Payouts = round2(response.Totals.Payouts)
+	response.Totals.BonusCost = round2(response.Totals.BonusCost)
+	response.Totals.GGR = round2(response.Totals.Stakes - response.Totals.Payouts)
+	response.Totals.NGR = round2(response.Totals.GGR - response.Totals.BonusCost)
+	response.Totals.Hold = percent(response.Totals.GGR, response.Totals.Stakes)
+
+	if format == "csv" {
+		writeFinancialReportCSV(ctx, response)
+		return
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// writeFinancialReportCSV envia o relatório em CSV, com a linha de totais no final
+func writeFinancialReportCSV(ctx *gin.Context, report model.FinancialReportResponse) {
+	name := []string{"relatorio-financeiro", report.Interval}
+	if report.GroupBy != "" {
+		name = append(name, report.GroupBy)
+	}
+	name = append(name, time.Now().Format("20060102"))
+
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, strings.Join(name, "-")))
+	ctx.Status(http.StatusOK)
+
+	w := csv.NewWriter(ctx.Writer)
+	w.Write([]string{"periodo", "grupo", "descricao", "apostas", "apostado", "premios", "ggr", "custo_bonus", "ngr", "hold_percentual"})
+	for _, r := range report.Rows {
+		w.Write([]string{
+			r.Period.Format("2006-01-02"),
+			r.GroupKey,
+			r.GroupLabel,
+			strconv.Itoa(r.Bets),
+			money(r.Stakes),
+			money(r.Payouts),
+			money(r.GGR),
+			money(r.BonusCost),
+			money(r.NGR),
+			money(r.Hold),
+		})
+	}
+	t := report.Totals
+	w.Write([]string{"total", "", "", strconv.Itoa(t.Bets), money(t.Stakes), money(t.Payouts),
+		money(t.GGR), money(t.BonusCost), money(t.NGR), money(t.Hold)})
+	w.Flush()
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// eventSnapshot serializa o evento para o histórico de revisões
+func eventSnapshot(event *model.MatchEvent) string {
+	if event == nil {
+		return ""
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// goalContribution retorna quanto um evento soma ao placar do tempo
+// regulamentar e da prorrogação de cada time
+func goalContribution(event *model.MatchEvent, match model.Match) (home, away, extraHome, extraAway int) {
+	if event == nil || event.EventType != model.EventTypeGoal {
+		return
+	}
+	isHome := goalSide(*event, match) == model.SelectionHome
+	switch event.Period {
+	case model.PeriodFirstHalf, model.PeriodSecondHalf:
+		if isHome {
+			home = 1
+		} else {
+			away = 1
+		}
+	case model.PeriodExtraTimeFirst, model.PeriodExtraTimeSecond:
+		if isHome {
+			extraHome = 1
+		} else {
+			extraAway = 1
+		}
+	}
+	return
+}
+
+// addScore soma delta ao placar sem deixá-lo negativo
+func addScore(score *int, delta int) {
+	*score += delta
+	if *score < 0 {
+		*score = 0
+	}
+}
+
+// applyEventCorrection ajusta o placar de uma partida encerrada conforme a
+// alteração de um gol e reliquida as apostas afetadas. before e after são o
+// evento antes e depois da alteração (nil na criação e na exclusão).
+// O chaveamento já decidido não é recalculado. Retorna quantas apostas foram
+// reliquidadas e se o placar mudou.
+func applyEventCorrection(tx *gorm.DB, match *model.Match, before, after *model.MatchEvent, reason string) (int, bool, error) {
+	if match.Status != model.MatchStatusFinished {
+		return 0, false, nil
+	}
+
+	oldHome, oldAway, oldExtraHome, oldExtraAway := goalContribution(before, *match)
+	newHome, newAway, newExtraHome, newExtraAway := goalContribution(after, *match)
+	scoreChanged := oldHome != newHome || oldAway != newAway ||
+		(match.HomeExtraTimeScore != nil && match.AwayExtraTimeScore != nil &&
+			(oldExtraHome != newExtraHome || oldExtraAway != newExtraAway))
+	if scoreChanged {
+		addScore(&match.HomeScore, newHome-oldHome)
+		addScore(&match.AwayScore, newAway-oldAway)
+		if match.HomeExtraTimeScore != nil && match.AwayExtraTimeScore != nil {
+			addScore(match.HomeExtraTimeScore, newExtraHome-oldExtraHome)
+			addScore(match.AwayExtraTimeScore, newExtraAway-oldExtraAway)
+		}
+		if err := tx.Save(match).Error; err != nil {
+			return 0, false, err
+		}
+	}
+
+	// Mesmo sem mudar o placar, a ordem ou o período dos gols decide outros mercados
+	resettled, err := resettleMatchBets(tx, *match, reason)
+	if err != nil {
+		return 0, false, err
+	}
+	return resettled, scoreChanged, nil
+}
+
+// lockMatch relê a partida com bloqueio, dentro da transação, para que
+// correções simultâneas não sobrescrevam o placar umas das outras
+func lockMatch(tx *gorm.DB, match *model.Match) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(match, match.ID).Error
+}
+
+// recordEventRevision grava uma revisão do evento
+func recordEventRevision(tx *gorm.DB, action string, before, after *model.MatchEvent, reason string, userID uint, resettled int) error {
+	event := after
+	if event == nil {
+		event = before
+	}
+	return tx.Create(&model.MatchEventRevision{
+		EventID:       event.ID,
+		MatchID:       event.MatchID,
+		Action:        action,
+		Before:        eventSnapshot(before),
+		After:         eventSnapshot(after),
+		Reason:        reason,
+		ChangedBy:     userID,
+		BetsResettled: resettled,
+	}).Error
+}
+
+// ListEventRevisions retorna o histórico de alterações de um evento, inclusive
+// de eventos já excluídos
+func (c *MatchEventController) ListEventRevisions(ctx *gin.Context) {
+	revisions := []model.MatchEventRevision{}
+	if err := c.DB.Where("event_id = ?", ctx.Param("id")).Order("id").Find(&revisions).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar revisões do evento"})
+		return
+	}
+	if len(revisions) == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Evento não encontrado"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, revisions)
+}
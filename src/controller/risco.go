@@ -0,0 +1,354 @@
+package controller
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/config"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"gorm.io/gorm"
+)
+
+// riskSettings são os parâmetros de ajuste automático de risco
+type riskSettings struct {
+	LiabilityStep  float64
+	OddsCut        float64
+	MaxStake       float64
+	LiabilityLimit float64
+}
+
+// bettingConfig são as regras operacionais das apostas usadas pelos handlers
+// sem controller (risco, limites e criador de apostas). São lidas uma vez na
+// inicialização e injetadas por SetBettingConfig.
+var bettingConfig config.BettingConfig
+
+// SetBettingConfig define as regras operacionais das apostas
+func SetBettingConfig(betting config.BettingConfig) {
+	bettingConfig = betting
+}
+
+// currentRiskSettings retorna os parâmetros de risco da configuração
+func currentRiskSettings() riskSettings {
+	betting := bettingConfig
+	return riskSettings{
+		LiabilityStep:  betting.RiskLiabilityStep,
+		OddsCut:        betting.RiskOddsCut,
+		MaxStake:       betting.RiskMaxStake,
+		LiabilityLimit: betting.RiskLiabilityLimit,
+	}
+}
+
+// adjustments retorna o fator das cotações e o limite de aposta para a
+// responsabilidade informada. Abaixo da primeira faixa não há ajuste.
+func (s riskSettings) adjustments(liability float64) (level int, oddsFactor, maxStake float64) {
+	if s.LiabilityStep <= 0 || liability < s.LiabilityStep {
+		return 0, 1, 0
+	}
+	level = int(liability / s.LiabilityStep)
+	oddsFactor = math.Pow(1-s.OddsCut, float64(level))
+	maxStake = math.Round(s.MaxStake/math.Pow(2, float64(level))*100) / 100
+	return level, oddsFactor, maxStake
+}
+
+// betMarket relaciona o tipo e o palpite de uma aposta à opção de mercado
+// usada no controle de risco e nas cotações publicadas
+func betMarket(betType, selection string) (string, string) {
+	switch betType {
+	case model.BetTypeWin:
+		return model.MarketMatchResult, model.SelectionHome
+	case model.BetTypeDraw:
+		return model.MarketMatchResult, model.SelectionDraw
+	case model.BetTypeLoss:
+		return model.MarketMatchResult, model.SelectionAway
+	case model.BetTypeBothTeamsScore:
+		return model.MarketBothTeamsScore, "yes"
+	case model.BetTypeOverUnder, model.BetTypeTotalGoals:
+		return model.MarketTotalGoals, selection
+	}
+	return betType, selection
+}
+
+// riskSelection identifica a opção no controle de risco; nos mercados de
+// linha a linha faz parte da opção ("over 2.5", "home -0.75")
+func riskSelection(selection string, line *float64) string {
+	if line == nil {
+		return selection
+	}
+	return fmt.Sprintf("%s %g", selection, *line)
+}
+
+// riskBook identifica o grupo de opções excludentes de um mercado, cujas
+// apostas pagam umas às outras. Nos mercados de linha cada linha é um grupo;
+// o handicap do visitante é o inverso do handicap do mandante.
+func riskBook(market, selection string, line *float64) string {
+	if line == nil {
+		return market
+	}
+	l := *line
+	if market == model.MarketAsianHandicap && selection == model.SelectionAway {
+		l = -l
+	}
+	return fmt.Sprintf("%s %g", market, l)
+}
+
+// riskKey identifica uma opção de mercado
+type riskKey struct {
+	Market    string
+	Selection string
+}
+
+// riskRow é a exposição agregada de um tipo de aposta, palpite e linha
+type riskRow struct {
+	BetType   string
+	Selection string
+	Line      *float64
+	BetCount  int
+	Stakes    float64
+	Payouts   float64
+}
+
+// refreshRiskPositions recalcula a exposição da partida a partir das apostas
+// pendentes, aplica os ajustes automáticos e gera alertas quando uma opção
+// passa do limite configurado. Os palpites pendentes de apostas de sistema
+// entram com as combinações pendentes que os contêm, considerando o pior
+// caso, em que os demais palpites também ganham.
+func refreshRiskPositions(tx *gorm.DB, matchID uint) error {
+	var rows []riskRow
+	if err := tx.Model(&model.Bet{}).
+		Select("bet_type, COALESCE(selection, '') AS selection, line, COUNT(*) AS bet_count, SUM(amount) AS stakes, SUM(amount * odds) AS payouts").
+		Where("match_id = ? AND status = ?", matchID, model.BetStatusPending).
+		Group("bet_type, COALESCE(selection, ''), line").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	var legRows []riskRow
+	if err := tx.Table("system_bet_legs AS l").
+		Select("l.bet_type, COALESCE(l.selection, '') AS selection, l.line, COUNT(DISTINCT l.id) AS bet_count, SUM(c.stake) AS stakes, SUM(c.stake * c.odds) AS payouts").
+		Joins("JOIN bet_combinations c ON c.system_bet_id = l.system_bet_id AND c.status = ? AND '-' || c.legs || '-' LIKE '%-' || l.position || '-%'",
+			model.BetStatusPending).
+		Where("l.match_id = ? AND l.status = ?", matchID, model.BetStatusPending).
+		// Combinações com um palpite já perdido não pagam mais nada
+		Where("NOT EXISTS (SELECT 1 FROM system_bet_legs o WHERE o.system_bet_id = c.system_bet_id AND o.status = ? AND '-' || c.legs || '-' LIKE '%-' || o.position || '-%')",
+			model.BetStatusLost).
+		Group("l.bet_type, COALESCE(l.selection, ''), l.line").
+		Scan(&legRows).Error; err != nil {
+		return err
+	}
+	rows = append(rows, legRows...)
+
+	positions := make(map[riskKey]*model.RiskPosition)
+	books := make(map[riskKey]string)
+	bookStakes := make(map[string]float64)
+	for _, r := range rows {
+		market, selection := betMarket(r.BetType, r.Selection)
+		key := riskKey{market, riskSelection(selection, r.Line)}
+		p, ok := positions[key]
+		if !ok {
+			p = &model.RiskPosition{MatchID: matchID, Market: key.Market, Selection: key.Selection}
+			positions[key] = p
+		}
+		p.BetCount += r.BetCount
+		p.Stakes += r.Stakes
+		p.Payouts += r.Payouts
+		books[key] = riskBook(market, selection, r.Line)
+		bookStakes[books[key]] += r.Stakes
+	}
+
+	var existing []model.RiskPosition
+	if err := tx.Where("match_id = ?", matchID).Find(&existing).Error; err != nil {
+		return err
+	}
+	for _, old := range existing {
+		key := riskKey{old.Market, old.Selection}
+		if p, ok := positions[key]; ok {
+			p.ID = old.ID
+			continue
+		}
+		if err := tx.Delete(&old).Error; err != nil {
+			return err
+		}
+	}
+
+	var openAlerts []model.RiskAlert
+	if err := tx.Where("match_id = ? AND status = ?", matchID, model.RiskAlertStatusOpen).
+		Find(&openAlerts).Error; err != nil {
+		return err
+	}
+	alerted := make(map[riskKey]bool, len(openAlerts))
+	for _, a := range openAlerts {
+		alerted[riskKey{a.Market, a.Selection}] = true
+	}
+
+	settings := currentRiskSettings()
+	for key, p := range positions {
+		p.Liability = math.Round((p.Payouts-bookStakes[books[key]])*100) / 100
+		p.Level, p.OddsFactor, p.MaxStake = settings.adjustments(p.Liability)
+		if err := tx.Save(p).Error; err != nil {
+			return err
+		}
+
+		if settings.LiabilityLimit > 0 && p.Liability > settings.LiabilityLimit && !alerted[key] {
+			if err := tx.Create(&model.RiskAlert{
+				MatchID:        matchID,
+				Market:         p.Market,
+				Selection:      p.Selection,
+				Liability:      p.Liability,
+				LiabilityLimit: settings.LiabilityLimit,
+				Status:         model.RiskAlertStatusOpen,
+			}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// adjustedOdds aplica à cotação aprovada o fator de risco da opção
+func adjustedOdds(odds, factor float64) float64 {
+	return math.Max(math.Round(odds*factor*100)/100, 1.01)
+}
+
+// checkBetRisk verifica a aposta contra a cotação publicada e o limite de
+// aposta da opção. Opções sem cotação aprovada não aceitam apostas, pois a
+// cotação informada pelo cliente não tem como ser conferida; a exceção é o
+// criador de apostas, cotado pelo próprio servidor. Retorna a mensagem de
+// erro e os detalhes, ou "" se a aposta for aceita.
+func checkBetRisk(db *gorm.DB, matchID uint, betType, selection string, line *float64, amount, odds float64) (string, gin.H, error) {
+	market, sel := betMarket(betType, selection)
+
+	var position model.RiskPosition
+	err := db.Where("match_id = ? AND market = ? AND selection = ?", matchID, market, riskSelection(sel, line)).First(&position).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return "", nil, err
+	}
+	if err == gorm.ErrRecordNotFound {
+		position.OddsFactor = 1
+	}
+
+	if position.MaxStake > 0 && amount > position.MaxStake {
+		return fmt.Sprintf("O valor máximo para esta aposta no momento é R$ %.2f", position.MaxStake),
+			gin.H{"max_stake": position.MaxStake}, nil
+	}
+
+	query := db.Where("match_id = ? AND market = ? AND selection = ? AND status = ?",
+		matchID, market, sel, model.OddsStatusApproved)
+	if line == nil {
+		query = query.Where("line IS NULL")
+	} else {
+		query = query.Where("line = ?", *line)
+	}
+	var published model.MarketOdds
+	err = query.Order("id DESC").First(&published).Error
+	if err == gorm.ErrRecordNotFound {
+		if betType == model.BetTypeBetBuilder {
+			return "", nil, nil
+		}
+		return "Esta opção não está disponível para apostas no momento", gin.H{"market": market, "selection": riskSelection(sel, line)}, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	current := adjustedOdds(published.Odds, position.OddsFactor)
+	if odds > current {
+		return "A cotação desta aposta mudou", gin.H{"current_odds": current}, nil
+	}
+	return "", nil, nil
+}
+
+type RiskController struct {
+	DB *gorm.DB
+}
+
+func NewRiskController(db *gorm.DB) *RiskController {
+	return &RiskController{DB: db}
+}
+
+// GetMatchRisk retorna a exposição da casa em cada opção da partida e os
+// alertas gerados
+func (c *RiskController) GetMatchRisk(ctx *gin.Context) {
+	var match model.Match
+	if err := c.DB.First(&match, ctx.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Partida não encontrada"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar partida"})
+		return
+	}
+
+	settings := currentRiskSettings()
+	response := model.RiskMatchResponse{
+		MatchID:       match.ID,
+		Status:        match.Status,
+		MarketStatus:  match.MarketStatus,
+		Positions:     []model.RiskPosition{},
+		Alerts:        []model.RiskAlert{},
+		LiabilityStep: settings.LiabilityStep,
+		Limit:         settings.LiabilityLimit,
+	}
+	if err := c.DB.Where("match_id = ?", match.ID).Order("liability DESC").Find(&response.Positions).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar exposição da partida"})
+		return
+	}
+	if err := c.DB.Where("match_id = ?", match.ID).Order("created_at DESC").Find(&response.Alerts).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar alertas da partida"})
+		return
+	}
+	for _, p := range response.Positions {
+		if p.Liability > response.WorstCase {
+			response.WorstCase = p.Liability
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ListAlerts lista os alertas de risco, com filtro opcional de status
+func (c *RiskController) ListAlerts(ctx *gin.Context) {
+	query := c.DB.Model(&model.RiskAlert{})
+	if status := ctx.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	alerts := []model.RiskAlert{}
+	if err := query.Order("created_at DESC").Find(&alerts).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar alertas"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, alerts)
+}
+
+// AcknowledgeAlert marca um alerta como visto. Um novo alerta para a mesma
+// opção só é gerado depois disso.
+func (c *RiskController) AcknowledgeAlert(ctx *gin.Context) {
+	var alert model.RiskAlert
+	if err := c.DB.First(&alert, ctx.Param("id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Alerta não encontrado"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar alerta"})
+		return
+	}
+	if alert.Status != model.RiskAlertStatusOpen {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "O alerta já foi reconhecido"})
+		return
+	}
+
+	userID := ctx.GetUint("user_id")
+	now := time.Now()
+	alert.Status = model.RiskAlertStatusAcknowledged
+	alert.AcknowledgedBy = &userID
+	alert.AcknowledgedAt = &now
+	if err := c.DB.Save(&alert).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar alerta"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, alert)
+}
@@ -0,0 +1,326 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/config"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"gorm.io/gorm"
+)
+
+// Status do jogador relacionados às suspensões
+const (
+	playerStatusActive    = "active"
+	playerStatusSuspended = "suspended"
+)
+
+// atLeastOne protege as regras disciplinares de valores inválidos gravados no torneio
+func atLeastOne(value int) int {
+	if value < 1 {
+		return 1
+	}
+	return value
+}
+
+// applyCardSuspension gera a suspensão decorrente de um cartão, conforme as
+// regras disciplinares do torneio da partida. Retorna nil quando o cartão
+// não suspende o jogador.
+func applyCardSuspension(tx *gorm.DB, match model.Match, event model.MatchEvent) (*model.PlayerSuspension, error) {
+	var tournament model.Tournament
+	if err := tx.First(&tournament, match.TournamentID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	reason := ""
+	matches := 0
+	switch event.CardType {
+	case model.CardTypeRed:
+		reason = model.SuspensionReasonRedCard
+		matches = atLeastOne(tournament.RedCardSuspensionMatches)
+	case model.CardTypeYellow:
+		var inMatch int64
+		if err := tx.Model(&model.MatchEvent{}).
+			Where("match_id = ? AND player_id = ? AND event_type = ? AND card_type = ?",
+				match.ID, event.PlayerID, model.EventTypeCard, model.CardTypeYellow).
+			Count(&inMatch).Error; err != nil {
+			return nil, err
+		}
+		if inMatch == 2 {
+			// O segundo amarelo na mesma partida equivale a uma expulsão, e o
+			// primeiro deixa de acumular: a suspensão que ele gerou é revista
+			reason = model.SuspensionReasonSecondYellow
+			matches = atLeastOne(tournament.RedCardSuspensionMatches)
+			if err := reconcileYellowAccumulation(tx, tournament, event.PlayerID); err != nil {
+				return nil, err
+			}
+			break
+		}
+		if inMatch > 2 {
+			return nil, nil
+		}
+
+		accumulated, err := accumulatedYellows(tx, tournament.ID, event.PlayerID)
+		if err != nil {
+			return nil, err
+		}
+		if accumulated == 0 || accumulated%int64(atLeastOne(tournament.YellowCardsForSuspension)) != 0 {
+			return nil, nil
+		}
+		reason = model.SuspensionReasonYellowAccumulation
+		matches = atLeastOne(tournament.YellowSuspensionMatches)
+	default:
+		return nil, nil
+	}
+
+	suspension := model.PlayerSuspension{
+		PlayerID:         event.PlayerID,
+		TeamID:           event.TeamID,
+		TournamentID:     tournament.ID,
+		MatchID:          match.ID,
+		EventID:          event.ID,
+		Reason:           reason,
+		MatchesTotal:     matches,
+		MatchesRemaining: matches,
+		Status:           model.SuspensionStatusActive,
+	}
+	if err := tx.Create(&suspension).Error; err != nil {
+		return nil, err
+	}
+	if err := syncPlayerSuspensionStatus(tx, event.PlayerID); err != nil {
+		return nil, err
+	}
+	return &suspension, nil
+}
+
+// cancelCardSuspension cancela a suspensão gerada por um cartão excluído.
+// Sem um amarelo, a expulsão por dois amarelos da partida deixa de valer e a
+// contagem de amarelos acumulados é refeita.
+func cancelCardSuspension(tx *gorm.DB, match model.Match, event model.MatchEvent) error {
+	if err := tx.Model(&model.PlayerSuspension{}).
+		Where("event_id = ? AND status = ?", event.ID, model.SuspensionStatusActive).
+		Update("status", model.SuspensionStatusCancelled).Error; err != nil {
+		return err
+	}
+
+	if event.CardType == model.CardTypeYellow {
+		var tournament model.Tournament
+		err := tx.First(&tournament, match.TournamentID).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		if err == nil {
+			var inMatch int64
+			if err := tx.Model(&model.MatchEvent{}).
+				Where("match_id = ? AND player_id = ? AND event_type = ? AND card_type = ?",
+					match.ID, event.PlayerID, model.EventTypeCard, model.CardTypeYellow).
+				Count(&inMatch).Error; err != nil {
+				return err
+			}
+			if inMatch < 2 {
+				if err := tx.Model(&model.PlayerSuspension{}).
+					Where("match_id = ? AND player_id = ? AND reason = ? AND status = ?",
+						match.ID, event.PlayerID, model.SuspensionReasonSecondYellow, model.SuspensionStatusActive).
+					Update("status", model.SuspensionStatusCancelled).Error; err != nil {
+					return err
+				}
+			}
+			if err := reconcileYellowAccumulation(tx, tournament, event.PlayerID); err != nil {
+				return err
+			}
+		}
+	}
+	return syncPlayerSuspensionStatus(tx, event.PlayerID)
+}
+
+// accumulatingYellows seleciona os amarelos do jogador no torneio que entram
+// na acumulação. Amarelos das partidas em que ele foi expulso por dois
+// amarelos não acumulam.
+func accumulatingYellows(tx *gorm.DB, tournamentID, playerID uint) *gorm.DB {
+	return tx.Model(&model.MatchEvent{}).
+		Joins("JOIN matches ON matches.id = match_events.match_id").
+		Where("matches.tournament_id = ? AND match_events.player_id = ? AND match_events.event_type = ? AND match_events.card_type = ?",
+			tournamentID, playerID, model.EventTypeCard, model.CardTypeYellow).
+		Where(`match_events.match_id NOT IN (
+			SELECT match_id FROM match_events
+			WHERE player_id = ? AND event_type = ? AND card_type = ?
+			GROUP BY match_id HAVING COUNT(*) >= 2)`,
+			playerID, model.EventTypeCard, model.CardTypeYellow)
+}
+
+// accumulatedYellows conta os amarelos do jogador que entram na acumulação
+func accumulatedYellows(tx *gorm.DB, tournamentID, playerID uint) (int64, error) {
+	var accumulated int64
+	err := accumulatingYellows(tx, tournamentID, playerID).Count(&accumulated).Error
+	return accumulated, err
+}
+
+// reconcileYellowAccumulation ajusta as suspensões por acumulação do jogador
+// à contagem atual de amarelos. As excedentes ainda ativas são canceladas, da
+// mais recente para a mais antiga; se faltar uma, ela é gerada pelo último
+// amarelo que acumula. Suspensões já cumpridas não são desfeitas.
+func reconcileYellowAccumulation(tx *gorm.DB, tournament model.Tournament, playerID uint) error {
+	accumulated, err := accumulatedYellows(tx, tournament.ID, playerID)
+	if err != nil {
+		return err
+	}
+	owed := int(accumulated / int64(atLeastOne(tournament.YellowCardsForSuspension)))
+
+	var suspensions []model.PlayerSuspension
+	if err := tx.Where("tournament_id = ? AND player_id = ? AND reason = ? AND status <> ?",
+		tournament.ID, playerID, model.SuspensionReasonYellowAccumulation, model.SuspensionStatusCancelled).
+		Order("id DESC").
+		Find(&suspensions).Error; err != nil {
+		return err
+	}
+
+	excess := len(suspensions) - owed
+	for _, s := range suspensions {
+		if excess <= 0 {
+			break
+		}
+		if s.Status != model.SuspensionStatusActive {
+			continue
+		}
+		if err := tx.Model(&s).Update("status", model.SuspensionStatusCancelled).Error; err != nil {
+			return err
+		}
+		excess--
+	}
+	if excess >= 0 {
+		return nil
+	}
+
+	// Falta uma suspensão: o amarelo que agora completa a contagem a gera
+	var last model.MatchEvent
+	err = accumulatingYellows(tx, tournament.ID, playerID).
+		Order("matches.start_time DESC, match_events.minute DESC, match_events.id DESC").
+		First(&last).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+	matches := atLeastOne(tournament.YellowSuspensionMatches)
+	return tx.Create(&model.PlayerSuspension{
+		PlayerID:         playerID,
+		TeamID:           last.TeamID,
+		TournamentID:     tournament.ID,
+		MatchID:          last.MatchID,
+		EventID:          last.ID,
+		Reason:           model.SuspensionReasonYellowAccumulation,
+		MatchesTotal:     matches,
+		MatchesRemaining: matches,
+		Status:           model.SuspensionStatusActive,
+	}).Error
+}
+
+// serveSuspensions desconta uma partida das suspensões ativas dos jogadores
+// dos dois times no torneio. A partida em que o cartão foi recebido não conta.
+func serveSuspensions(tx *gorm.DB, match model.Match) (int, error) {
+	var suspensions []model.PlayerSuspension
+	if err := tx.Where("tournament_id = ? AND team_id IN ? AND status = ? AND match_id <> ?",
+		match.TournamentID, []uint{match.HomeTeamID, match.AwayTeamID}, model.SuspensionStatusActive, match.ID).
+		Find(&suspensions).Error; err != nil {
+		return 0, err
+	}
+
+	served := 0
+	for _, s := range suspensions {
+		s.MatchesRemaining--
+		if s.MatchesRemaining <= 0 {
+			s.MatchesRemaining = 0
+			s.Status = model.SuspensionStatusServed
+			served++
+		}
+		if err := tx.Save(&s).Error; err != nil {
+			return 0, err
+		}
+		if s.Status == model.SuspensionStatusServed {
+			if err := syncPlayerSuspensionStatus(tx, s.PlayerID); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return served, nil
+}
+
+// syncPlayerSuspensionStatus mantém o status do jogador coerente com as
+// suspensões ativas. Lesionados e inativos mantêm o status atual.
+func syncPlayerSuspensionStatus(tx *gorm.DB, playerID uint) error {
+	var active int64
+	if err := tx.Model(&model.PlayerSuspension{}).
+		Where("player_id = ? AND status = ?", playerID, model.SuspensionStatusActive).
+		Count(&active).Error; err != nil {
+		return err
+	}
+
+	if active > 0 {
+		return tx.Model(&model.Player{}).
+			Where("id = ? AND status = ?", playerID, playerStatusActive).
+			Update("status", playerStatusSuspended).Error
+	}
+	return tx.Model(&model.Player{}).
+		Where("id = ? AND status = ?", playerID, playerStatusSuspended).
+		Update("status", playerStatusActive).Error
+}
+
+// suspendedInTournament indica, para cada jogador, se ele está impedido de
+// jogar no torneio. Um jogador com status suspenso sem suspensões
+// automáticas ativas foi suspenso manualmente e fica impedido em qualquer torneio.
+func suspendedInTournament(db *gorm.DB, tournamentID uint, players []model.Player) (map[uint]bool, error) {
+	ids := make([]uint, 0, len(players))
+	for _, p := range players {
+		ids = append(ids, p.ID)
+	}
+
+	var suspensions []model.PlayerSuspension
+	if len(ids) > 0 {
+		if err := db.Where("player_id IN ? AND status = ?", ids, model.SuspensionStatusActive).
+			Find(&suspensions).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	automatic := make(map[uint]bool)
+	suspended := make(map[uint]bool)
+	for _, s := range suspensions {
+		automatic[s.PlayerID] = true
+		if s.TournamentID == tournamentID {
+			suspended[s.PlayerID] = true
+		}
+	}
+	for _, p := range players {
+		if p.Status == playerStatusSuspended && !automatic[p.ID] {
+			suspended[p.ID] = true
+		}
+	}
+	return suspended, nil
+}
+
+// ListPlayerSuspensions lista as suspensões de um jogador, com filtro
+// opcional de status
+func ListPlayerSuspensions(c *gin.Context) {
+	var player model.Player
+	if err := config.DB.First(&player, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Jogador não encontrado"})
+		return
+	}
+
+	query := config.DB.Where("player_id = ?", player.ID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	suspensions := []model.PlayerSuspension{}
+	if err := query.Order("created_at DESC").Find(&suspensions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar suspensões do jogador"})
+		return
+	}
+
+	c.JSON(http.StatusOK, suspensions)
+}
@@ -0,0 +1,420 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+)
+
+// Valores padrão da geração de tabela
+const (
+	defaultKickoffTime   = "16:00"
+	defaultMatchDuration = 120
+	defaultReferee       = "A definir"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// pairing é um confronto ainda sem data
+type pairing struct {
+	Home uint
+	Away uint
+}
+
+// GenerateFixtures cria a tabela de jogos do torneio a partir dos times inscritos
+func (c *TournamentController) GenerateFixtures(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var tournament model.Tournament
+	if err := c.DB.First(&tournament, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Torneio não encontrado"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar torneio"})
+		return
+	}
+
+	var input model.FixtureGenerate
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if input.KickoffTime == "" {
+		input.KickoffTime = defaultKickoffTime
+	}
+	kickoff, err := time.Parse("15:04", input.KickoffTime)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Horário de início inválido, use o formato HH:MM"})
+		return
+	}
+	if input.MatchDuration == 0 {
+		input.MatchDuration = defaultMatchDuration
+	}
+	if input.Referee == "" {
+		input.Referee = defaultReferee
+	}
+	if len(input.MatchDays) == 0 {
+		input.MatchDays = []string{"saturday", "sunday"}
+	}
+
+	// Não gera a tabela duas vezes
+	var generated int64
+	if err := c.DB.Model(&model.Match{}).
+		Where("tournament_id = ? AND round > 0 AND status <> ?", tournament.ID, model.MatchStatusCancelled).
+		Count(&generated).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar partidas do torneio"})
+		return
+	}
+	if generated > 0 {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "A tabela de jogos deste torneio já foi gerada"})
+		return
+	}
+
+	var teamIDs []uint
+	if err := c.DB.Model(&model.TournamentTeam{}).Where("tournament_id = ?", tournament.ID).
+		Order("joined_at, team_id").Pluck("team_id", &teamIDs).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar times do torneio"})
+		return
+	}
+	if len(teamIDs) < 2 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "O torneio precisa de pelo menos dois times inscritos"})
+		return
+	}
+
+	// Cada item é uma data de jogo: uma rodada dos pontos corridos ou uma
+	// partida (ida ou volta) de uma fase do mata-mata
+	var slots []fixtureSlot
+	var firstRound []pairing
+	switch input.Format {
+	case model.FixtureFormatRoundRobin, model.FixtureFormatDoubleRoundRobin:
+		rounds, byes := roundRobin(teamIDs, input.Format == model.FixtureFormatDoubleRoundRobin)
+		for i := range rounds {
+			slots = append(slots, fixtureSlot{Round: i + 1, Pairings: rounds[i], Byes: byes[i]})
+		}
+	case model.FixtureFormatKnockout:
+		var phases int
+		firstRound, phases = knockoutPairings(teamIDs)
+		slots = knockoutSlots(firstRound, phases, input.Legs, input.SingleLegFinal)
+	}
+
+	// Datas disponíveis entre o início (ou amanhã) e o fim do torneio
+	dates := matchDates(tournament.StartDate, tournament.EndDate, input.MatchDays, kickoff)
+	if len(dates) < len(slots) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("O período do torneio tem %d datas disponíveis, mas são necessárias %d", len(dates), len(slots)),
+		})
+		return
+	}
+
+	duration := time.Duration(input.MatchDuration) * time.Minute
+	slotDates, err := c.scheduleRounds(slots, dates, duration)
+	if err != nil {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	var teams []model.Team
+	if err := c.DB.Where("id IN ?", teamIDs).Find(&teams).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar times do torneio"})
+		return
+	}
+	stadiums := make(map[uint]string, len(teams))
+	for _, team := range teams {
+		stadiums[team.ID] = team.Stadium
+	}
+
+	response := model.FixtureResponse{
+		TournamentID: tournament.ID,
+		Format:       input.Format,
+		Teams:        len(teamIDs),
+		DryRun:       input.DryRun,
+	}
+
+	err = c.DB.Transaction(func(tx *gorm.DB) error {
+		// No mata-mata, as partidas são criadas pelo chaveamento conforme os
+		// times de cada confronto são definidos
+		created := make(map[[2]int][]model.Match)
+		if input.Format == model.FixtureFormatKnockout && !input.DryRun {
+			legDates := make([][]time.Time, 0)
+			for i, slot := range slots {
+				if slot.Leg <= 1 {
+					legDates = append(legDates, nil)
+				}
+				legDates[slot.Round-1] = append(legDates[slot.Round-1], slotDates[i])
+			}
+			if _, err := createBracket(tx, tournament.ID, firstRound, legDates, input.AwayGoalsRule, input.MatchDuration); err != nil {
+				return err
+			}
+
+			var matches []model.Match
+			if err := tx.Where("tournament_id = ? AND bracket_tie_id IS NOT NULL", tournament.ID).
+				Order("id").Find(&matches).Error; err != nil {
+				return err
+			}
+			for _, m := range matches {
+				key := [2]int{m.Round, m.Leg}
+				created[key] = append(created[key], m)
+			}
+
+			bracket, err := buildBracket(tx, tournament.ID)
+			if err != nil {
+				return err
+			}
+			response.Bracket = &bracket
+		}
+
+		for i, slot := range slots {
+			fixtureRound := model.FixtureRound{Round: slot.Round, Leg: slot.Leg, Date: slotDates[i], Byes: slot.Byes}
+			if input.Format == model.FixtureFormatKnockout && !input.DryRun {
+				leg := slot.Leg
+				if leg == 0 {
+					leg = 1
+				}
+				for _, m := range created[[2]int{slot.Round, leg}] {
+					fixtureRound.Matches = append(fixtureRound.Matches, toMatchResponse(m))
+				}
+				response.Rounds = append(response.Rounds, fixtureRound)
+				continue
+			}
+
+			for _, p := range slot.Pairings {
+				match := model.Match{
+					TournamentID: tournament.ID,
+					Round:        slot.Round,
+					Leg:          slot.Leg,
+					HomeTeamID:   p.Home,
+					AwayTeamID:   p.Away,
+					StartTime:    slotDates[i],
+					EndTime:      slotDates[i].Add(duration),
+					Status:       model.MatchStatusScheduled,
+					MarketStatus: model.MarketStatusOpen,
+					Stadium:      stadiums[p.Home],
+					Referee:      input.Referee,
+				}
+				if !input.DryRun {
+					if err := tx.Create(&match).Error; err != nil {
+						return err
+					}
+				}
+				fixtureRound.Matches = append(fixtureRound.Matches, toMatchResponse(match))
+			}
+			response.Rounds = append(response.Rounds, fixtureRound)
+		}
+		return nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gravar tabela de jogos", "details": err.Error()})
+		return
+	}
+
+	status := http.StatusCreated
+	if input.DryRun {
+		status = http.StatusOK
+	}
+	ctx.JSON(status, response)
+}
+
+// fixtureSlot é uma data de jogo da tabela: uma rodada dos pontos corridos ou
+// a ida ou volta de uma fase do mata-mata
+type fixtureSlot struct {
+	Round    int
+	Leg      int
+	Pairings []pairing
+	Byes     []uint
+}
+
+// knockoutSlots lista as datas de jogo do mata-mata, fase a fase. Apenas a
+// primeira fase tem confrontos conhecidos; nas demais, os times dependem dos
+// resultados.
+func knockoutSlots(firstRound []pairing, phases, legs int, singleLegFinal bool) []fixtureSlot {
+	if legs == 0 {
+		legs = 1
+	}
+
+	var slots []fixtureSlot
+	for phase := 1; phase <= phases; phase++ {
+		phaseLegs := legs
+		if phase == phases && singleLegFinal {
+			phaseLegs = 1
+		}
+		for leg := 1; leg <= phaseLegs; leg++ {
+			slot := fixtureSlot{Round: phase}
+			if phaseLegs > 1 {
+				slot.Leg = leg
+			}
+			if phase == 1 {
+				for _, p := range firstRound {
+					switch {
+					case p.Home == 0:
+						slot.Byes = append(slot.Byes, p.Away)
+					case p.Away == 0:
+						slot.Byes = append(slot.Byes, p.Home)
+					case leg == 2:
+						slot.Pairings = append(slot.Pairings, pairing{Home: p.Away, Away: p.Home})
+					default:
+						slot.Pairings = append(slot.Pairings, p)
+					}
+				}
+			}
+			slots = append(slots, slot)
+		}
+	}
+	return slots
+}
+
+// roundRobin gera as rodadas pelo método do círculo. Com número ímpar de
+// times, um deles folga em cada rodada. No turno e returno, o returno repete
+// o turno com mando invertido.
+func roundRobin(teamIDs []uint, double bool) ([][]pairing, [][]uint) {
+	list := append([]uint(nil), teamIDs...)
+	if len(list)%2 == 1 {
+		// 0 representa a folga. Na posição fixa, os jogos de cada time ficam
+		// divididos igualmente entre casa e fora
+		list = append([]uint{0}, list...)
+	}
+	n := len(list)
+
+	var rounds [][]pairing
+	var byes [][]uint
+	for r := 0; r < n-1; r++ {
+		var round []pairing
+		var roundByes []uint
+		for i := 0; i < n/2; i++ {
+			home, away := list[i], list[n-1-i]
+			// Alterna o mando para equilibrar jogos em casa e fora
+			if (i == 0 && r%2 == 1) || (i > 0 && i%2 == 1) {
+				home, away = away, home
+			}
+			switch {
+			case home == 0:
+				roundByes = append(roundByes, away)
+			case away == 0:
+				roundByes = append(roundByes, home)
+			default:
+				round = append(round, pairing{Home: home, Away: away})
+			}
+		}
+		rounds = append(rounds, round)
+		byes = append(byes, roundByes)
+
+		// Mantém o primeiro fixo e gira os demais
+		last := list[n-1]
+		copy(list[2:], list[1:n-1])
+		list[1] = last
+	}
+
+	if double {
+		first := len(rounds)
+		for r := 0; r < first; r++ {
+			var round []pairing
+			for _, p := range rounds[r] {
+				round = append(round, pairing{Home: p.Away, Away: p.Home})
+			}
+			rounds = append(rounds, round)
+			byes = append(byes, byes[r])
+		}
+	}
+
+	return rounds, byes
+}
+
+// matchDates lista as datas de jogo nos dias da semana permitidos
+func matchDates(start, end time.Time, days []string, kickoff time.Time) []time.Time {
+	allowed := make(map[time.Weekday]bool, len(days))
+	for _, day := range days {
+		allowed[weekdays[strings.ToLower(day)]] = true
+	}
+
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	if start.Before(tomorrow) {
+		start = tomorrow
+	}
+
+	loc := start.Location()
+	var dates []time.Time
+	for d := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc); !d.After(end); d = d.AddDate(0, 0, 1) {
+		if !allowed[d.Weekday()] {
+			continue
+		}
+		date := time.Date(d.Year(), d.Month(), d.Day(), kickoff.Hour(), kickoff.Minute(), 0, 0, loc)
+		if date.After(end) {
+			break
+		}
+		dates = append(dates, date)
+	}
+	return dates
+}
+
+// scheduleRounds distribui as datas de jogo pelas datas disponíveis. Cada
+// uma fica na primeira data a partir da sua posição ideal em que nenhum dos
+// seus times já tenha outra partida no mesmo horário.
+func (c *TournamentController) scheduleRounds(slots []fixtureSlot, dates []time.Time, duration time.Duration) ([]time.Time, error) {
+	scheduled := make([]time.Time, len(slots))
+	next := 0
+	for i, slot := range slots {
+		var teams []uint
+		for _, p := range slot.Pairings {
+			teams = append(teams, p.Home, p.Away)
+		}
+
+		idx := i * len(dates) / len(slots)
+		if idx < next {
+			idx = next
+		}
+
+		found := false
+		for ; len(dates)-idx >= len(slots)-i; idx++ {
+			if len(teams) == 0 {
+				found = true
+				break
+			}
+			busy, err := teamsBusy(c.DB, teams, dates[idx], dates[idx].Add(duration))
+			if err != nil {
+				return nil, err
+			}
+			if !busy {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("não foi possível encaixar a rodada %d sem conflito de horário com outras partidas dos times", slot.Round)
+		}
+
+		scheduled[i] = dates[idx]
+		next = idx + 1
+	}
+	return scheduled, nil
+}
+
+// teamsBusy informa se algum dos times já tem partida entre start e end.
+// Partidas canceladas e adiadas não ocupam o horário.
+func teamsBusy(db *gorm.DB, teams []uint, start, end time.Time) (bool, error) {
+	var conflicts int64
+	if err := db.Model(&model.Match{}).Where(
+		"status NOT IN ? AND (home_team_id IN ? OR away_team_id IN ?) AND start_time <= ? AND end_time >= ?",
+		[]string{model.MatchStatusCancelled, model.MatchStatusPostponed}, teams, teams,
+		end, start,
+	).Count(&conflicts).Error; err != nil {
+		return false, err
+	}
+	return conflicts > 0, nil
+}
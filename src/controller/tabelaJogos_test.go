@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRoundRobin(t *testing.T) {
+	tests := []struct {
+		teams      int
+		double     bool
+		wantRounds int
+	}{
+		{2, false, 1},
+		{4, false, 3},
+		{5, false, 5},
+		{6, false, 5},
+		{4, true, 6},
+		{5, true, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d times, returno %v", tt.teams, tt.double), func(t *testing.T) {
+			teamIDs := make([]uint, tt.teams)
+			for i := range teamIDs {
+				teamIDs[i] = uint(i + 1)
+			}
+			rounds, byes := roundRobin(teamIDs, tt.double)
+			if len(rounds) != tt.wantRounds || len(byes) != tt.wantRounds {
+				t.Fatalf("%d rodadas e %d listas de folga, esperado %d", len(rounds), len(byes), tt.wantRounds)
+			}
+
+			// Cada time joga ou folga exatamente uma vez por rodada
+			for r := range rounds {
+				seen := make(map[uint]int)
+				for _, p := range rounds[r] {
+					seen[p.Home]++
+					seen[p.Away]++
+				}
+				for _, id := range byes[r] {
+					seen[id]++
+				}
+				for _, id := range teamIDs {
+					if seen[id] != 1 {
+						t.Errorf("rodada %d: time %d aparece %d vezes", r+1, id, seen[id])
+					}
+				}
+				if tt.teams%2 == 0 && len(byes[r]) != 0 {
+					t.Errorf("rodada %d: folga com número par de times", r+1)
+				}
+			}
+
+			// Cada mando de campo acontece uma vez no returno e, no turno único,
+			// cada par se enfrenta uma única vez
+			games := make(map[[2]uint]int)
+			home := make(map[uint]int)
+			byeCount := make(map[uint]int)
+			for r := range rounds {
+				for _, p := range rounds[r] {
+					games[[2]uint{p.Home, p.Away}]++
+					home[p.Home]++
+				}
+				for _, id := range byes[r] {
+					byeCount[id]++
+				}
+			}
+			for _, a := range teamIDs {
+				for _, b := range teamIDs {
+					if a >= b {
+						continue
+					}
+					ab, ba := games[[2]uint{a, b}], games[[2]uint{b, a}]
+					if tt.double && (ab != 1 || ba != 1) {
+						t.Errorf("%d x %d: %d jogos em casa de %d e %d em casa de %d, esperado 1 e 1", a, b, ab, a, ba, b)
+					}
+					if !tt.double && ab+ba != 1 {
+						t.Errorf("%d x %d: %d jogos, esperado 1", a, b, ab+ba)
+					}
+				}
+			}
+
+			matchesPerTeam := tt.teams - 1
+			for _, id := range teamIDs {
+				if tt.teams%2 == 1 && !tt.double && byeCount[id] != 1 {
+					t.Errorf("time %d folga %d vezes, esperado 1", id, byeCount[id])
+				}
+				if tt.double {
+					if home[id] != matchesPerTeam {
+						t.Errorf("time %d joga %d vezes em casa, esperado %d", id, home[id], matchesPerTeam)
+					}
+					continue
+				}
+				// No turno único, o mando fica equilibrado (exato com número ímpar de times)
+				tolerance := 1
+				if tt.teams%2 == 1 {
+					tolerance = 0
+				}
+				if away := matchesPerTeam - home[id]; home[id]-away > tolerance || away-home[id] > tolerance {
+					t.Errorf("time %d joga %d vezes em casa e %d fora", id, home[id], away)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lfdelima3/Backend-Go-Bet/src/config"
+	"github.com/lfdelima3/Backend-Go-Bet/src/model"
+	"github.com/lfdelima3/Backend-Go-Bet/src/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errTransferRejected indica uma transferência recusada dentro da transação
+var errTransferRejected = errors.New("transferência recusada")
+
+// playerTeamsAt retorna o time de cada jogador na data informada. Jogadores
+// sem nenhum vínculo registrado usam o time atual; jogadores sem vínculo na
+// data ficam fora do mapa.
+func playerTeamsAt(db *gorm.DB, players []model.Player, at time.Time) (map[uint]uint, error) {
+	teams := make(map[uint]uint, len(players))
+	if len(players) == 0 {
+		return teams, nil
+	}
+	ids := make([]uint, 0, len(players))
+	for _, p := range players {
+		ids = append(ids, p.ID)
+	}
+
+	var spells []model.PlayerSpell
+	if err := db.Where("player_id IN ?", ids).Find(&spells).Error; err != nil {
+		return nil, err
+	}
+
+	hasSpells := make(map[uint]bool)
+	for _, s := range spells {
+		hasSpells[s.PlayerID] = true
+		if (s.StartDate == nil || !s.StartDate.After(at)) && (s.EndDate == nil || s.EndDate.After(at)) {
+			teams[s.PlayerID] = s.TeamID
+		}
+	}
+	for _, p := range players {
+		if !hasSpells[p.ID] {
+			teams[p.ID] = p.TeamID
+		}
+	}
+	return teams, nil
+}
+
+// playerTeamAt retorna o time do jogador na data informada, ou 0 se ele não
+// tinha vínculo com nenhum time
+func playerTeamAt(db *gorm.DB, player model.Player, at time.Time) (uint, error) {
+	teams, err := playerTeamsAt(db, []model.Player{player}, at)
+	if err != nil {
+		return 0, err
+	}
+	return teams[player.ID], nil
+}
+
+// ListPlayerTransfers retorna o histórico de times do jogador, do mais antigo ao atual
+func ListPlayerTransfers(c *gin.Context) {
+	var player model.Player
+	if err := config.DB.First(&player, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Jogador não encontrado"})
+		return
+	}
+
+	spells := []model.PlayerSpell{}
+	if err := config.DB.Where("player_id = ?", player.ID).
+		Order("start_date ASC NULLS FIRST, id ASC").Find(&spells).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar transferências do jogador", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"player_id": player.ID, "team_id": player.TeamID, "data": spells})
+}
+
+// TransferPlayer encerra o vínculo atual do jogador e inicia um novo vínculo
+// com o time de destino na data da transferência
+func TransferPlayer(c *gin.Context) {
+	var input model.PlayerTransferCreate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if err := util.ValidateStruct(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if input.Date.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A data da transferência não pode estar no futuro"})
+		return
+	}
+
+	var team model.Team
+	if err := config.DB.First(&team, input.ToTeamID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Time não encontrado"})
+		return
+	}
+
+	var player model.Player
+	var spell model.PlayerSpell
+	status := http.StatusConflict
+	message := ""
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&player, c.Param("id")).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				status, message = http.StatusNotFound, "Jogador não encontrado"
+				return errTransferRejected
+			}
+			return err
+		}
+		if player.TeamID == input.ToTeamID {
+			message = "O jogador já pertence a este time"
+			return errTransferRejected
+		}
+
+		// O vínculo atual precisa ter começado antes da transferência
+		var current model.PlayerSpell
+		err := tx.Where("player_id = ? AND end_date IS NULL", player.ID).First(&current).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			current = model.PlayerSpell{PlayerID: player.ID, TeamID: player.TeamID}
+		case err != nil:
+			return err
+		case current.StartDate != nil && !input.Date.After(*current.StartDate):
+			message = "A data da transferência deve ser posterior ao início do vínculo atual"
+			return errTransferRejected
+		}
+
+		// Partidas do time antigo a partir da data não podem ter o jogador
+		var later int64
+		if err := tx.Model(&model.MatchEvent{}).
+			Joins("JOIN matches ON matches.id = match_events.match_id").
+			Where("match_events.player_id = ? AND match_events.team_id = ? AND matches.start_time >= ?",
+				player.ID, current.TeamID, input.Date).
+			Count(&later).Error; err != nil {
+			return err
+		}
+		if later == 0 {
+			if err := tx.Model(&model.MatchLineupPlayer{}).
+				Joins("JOIN matches ON matches.id = match_lineup_players.match_id").
+				Where("match_lineup_players.player_id = ? AND match_lineup_players.team_id = ? AND matches.start_time >= ?",
+					player.ID, current.TeamID, input.Date).
+				Count(&later).Error; err != nil {
+				return err
+			}
+		}
+		if later > 0 {
+			message = "O jogador tem partidas pelo time atual depois da data da transferência"
+			return errTransferRejected
+		}
+
+		number := player.Number
+		if input.Number != 0 {
+			number = input.Number
+		}
+		var clash int64
+		if err := tx.Model(&model.Player{}).
+			Where("team_id = ? AND number = ? AND id <> ?", input.ToTeamID, number, player.ID).
+			Count(&clash).Error; err != nil {
+			return err
+		}
+		if clash > 0 {
+			message = "Já existe um jogador com este número no time de destino"
+			return errTransferRejected
+		}
+
+		date := input.Date
+		current.EndDate = &date
+		if err := tx.Save(&current).Error; err != nil {
+			return err
+		}
+
+		fromTeamID := current.TeamID
+		spell = model.PlayerSpell{
+			PlayerID:   player.ID,
+			TeamID:     input.ToTeamID,
+			StartDate:  &date,
+			FromTeamID: &fromTeamID,
+			Fee:        input.Fee,
+			Notes:      input.Notes,
+		}
+		if err := tx.Create(&spell).Error; err != nil {
+			return err
+		}
+
+		player.TeamID = input.ToTeamID
+		player.Number = number
+		return tx.Save(&player).Error
+	})
+	if err != nil {
+		if errors.Is(err, errTransferRejected) {
+			c.JSON(status, gin.H{"error": message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao transferir jogador", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"player": player, "transfer": spell})
+}
@@ -234,14 +234,12 @@ func UpdateUser(c *gin.Context) {
 	if update.Role != "" {
 		user.Role = update.Role
 	}
-	if update.Balance != 0 {
-		user.Balance = update.Balance
-	}
 	if update.Status != "" {
 		user.Status = update.Status
 	}
 
-	if err := config.DB.Save(&user).Error; err != nil {
+	// O saldo só muda pelas apostas e pelos ajustes do suporte, que geram lançamentos no extrato
+	if err := config.DB.Omit("balance").Save(&user).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar usuário", "details": err.Error()})
 		return
 	}
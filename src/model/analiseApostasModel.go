@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+)
+
+// BetTypeAnalytics é o desempenho do usuário em um tipo de aposta. Valores
+// apostados e retornados consideram só as apostas liquidadas; StrikeRate é o
+// percentual de apostas liquidadas ganhas (inclusive meio ganho).
+type BetTypeAnalytics struct {
+	BetType       string  `json:"bet_type"`
+	Bets          int     `json:"bets"`
+	SettledBets   int     `json:"settled_bets"`
+	WonBets       int     `json:"won_bets"`
+	PendingBets   int     `json:"pending_bets"`
+	PendingStaked float64 `json:"pending_staked"`
+	Staked        float64 `json:"staked"`
+	Returned      float64 `json:"returned"`
+	ProfitLoss    float64 `json:"profit_loss"`
+	ROI           float64 `json:"roi"`
+	StrikeRate    float64 `json:"strike_rate"`
+	AverageOdds   float64 `json:"average_odds"`
+}
+
+// ProfitPoint é um ponto da curva de lucro, pela data de liquidação das apostas
+type ProfitPoint struct {
+	Period     time.Time `json:"period"`
+	Bets       int       `json:"bets"`
+	Staked     float64   `json:"staked"`
+	Returned   float64   `json:"returned"`
+	ProfitLoss float64   `json:"profit_loss"`
+	Cumulative float64   `json:"cumulative"`
+}
+
+// UserAnalyticsResponse é o painel de desempenho do usuário nas apostas
+type UserAnalyticsResponse struct {
+	UserID        uint               `json:"user_id"`
+	Bets          int                `json:"bets"`
+	SettledBets   int                `json:"settled_bets"`
+	WonBets       int                `json:"won_bets"`
+	PendingBets   int                `json:"pending_bets"`
+	PendingStaked float64            `json:"pending_staked"`
+	TotalStaked   float64            `json:"total_staked"`
+	TotalReturned float64            `json:"total_returned"`
+	ProfitLoss    float64            `json:"profit_loss"`
+	ROI           float64            `json:"roi"`
+	StrikeRate    float64            `json:"strike_rate"`
+	AverageOdds   float64            `json:"average_odds"`
+	ByBetType     []BetTypeAnalytics `json:"by_bet_type"`
+	Interval      string             `json:"interval"`
+	ProfitCurve   []ProfitPoint      `json:"profit_curve"`
+	GeneratedAt   time.Time          `json:"generated_at"`
+}
@@ -5,38 +5,49 @@ import (
 )
 
 type Bet struct {
-	ID           uint      `json:"id" gorm:"primaryKey"`
-	UserID       uint      `json:"user_id" validate:"required"`
-	MatchID      uint      `json:"match_id" validate:"required"`
-	BetType      string    `json:"bet_type" validate:"required,oneof=win draw loss over_under corners cards goals first_goal exact_score asian_handicap both_teams_score total_goals"`
-	Amount       float64   `json:"amount" validate:"required,min=1,valid_amount"`
-	Odds         float64   `json:"odds" validate:"required,min=1,valid_odds"`
-	Status       string    `json:"status" validate:"required,oneof=pending won lost cancelled"`
-	Result       string    `json:"result" validate:"omitempty,oneof=win draw loss"`
-	Payout       float64   `json:"payout" validate:"omitempty,min=0"`
-	CashoutValue float64   `json:"cashout_value" validate:"omitempty,min=0"`
-	IsCashout    bool      `json:"is_cashout"`
-	BonusApplied float64   `json:"bonus_applied" validate:"omitempty,min=0"`
-	PromotionID  *uint     `json:"promotion_id"`
-	BetLimit     float64   `json:"bet_limit" validate:"omitempty,min=0"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uint     `json:"id" gorm:"primaryKey"`
+	UserID       uint     `json:"user_id" validate:"required"`
+	MatchID      uint     `json:"match_id" validate:"required"`
+	BetType      string   `json:"bet_type" validate:"required,oneof=win draw loss over_under corners cards goals first_goal exact_score asian_handicap both_teams_score total_goals half_time_result bet_builder"`
+	Amount       float64  `json:"amount" validate:"required,min=1,valid_amount"`
+	Odds         float64  `json:"odds" validate:"required,min=1,valid_odds"`
+	Selection    string   `json:"selection" validate:"omitempty,max=10"`
+	Line         *float64 `json:"line"`
+	Status       string   `json:"status" validate:"required,oneof=pending won lost half_won half_lost push cancelled void"`
+	Result       string   `json:"result" validate:"omitempty,oneof=win draw loss"`
+	Payout       float64  `json:"payout" validate:"omitempty,min=0"`
+	CashoutValue float64  `json:"cashout_value" validate:"omitempty,min=0"`
+	IsCashout    bool     `json:"is_cashout"`
+	BonusApplied float64  `json:"bonus_applied" validate:"omitempty,min=0"`
+	PromotionID  *uint    `json:"promotion_id"`
+	BetLimit     float64  `json:"bet_limit" validate:"omitempty,min=0"`
+	// SettledManually indica que a situação foi definida pelo suporte e não
+	// deve ser refeita pelas correções dos dados da partida
+	SettledManually bool `json:"settled_manually"`
+	// SettledAt é a data da primeira liquidação; reliquidações não a alteram
+	SettledAt *time.Time `json:"settled_at"`
+	// Selections são os palpites das apostas do criador de apostas (bet_builder)
+	Selections []BetSelection `json:"selections,omitempty" gorm:"foreignKey:BetID"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
 }
 
 type BetCreate struct {
-	UserID      uint    `json:"user_id" validate:"required"`
-	MatchID     uint    `json:"match_id" validate:"required"`
-	BetType     string  `json:"bet_type" validate:"required,oneof=win draw loss over_under corners cards goals first_goal exact_score asian_handicap both_teams_score total_goals"`
-	Amount      float64 `json:"amount" validate:"required,min=1,valid_amount"`
-	Odds        float64 `json:"odds" validate:"required,min=1,valid_odds"`
-	PromotionID *uint   `json:"promotion_id"`
+	UserID      uint     `json:"user_id" validate:"required"`
+	MatchID     uint     `json:"match_id" validate:"required"`
+	BetType     string   `json:"bet_type" validate:"required,oneof=win draw loss over_under corners cards goals first_goal exact_score asian_handicap both_teams_score total_goals half_time_result"`
+	Amount      float64  `json:"amount" validate:"required,min=1,valid_amount"`
+	Odds        float64  `json:"odds" validate:"required,min=1,valid_odds"`
+	Selection   string   `json:"selection" validate:"omitempty,max=10"`
+	Line        *float64 `json:"line"`
+	PromotionID *uint    `json:"promotion_id"`
 }
 
 type BetUpdate struct {
-	BetType      string  `json:"bet_type" validate:"omitempty,oneof=win draw loss over_under corners cards goals first_goal exact_score asian_handicap both_teams_score total_goals"`
+	BetType      string  `json:"bet_type" validate:"omitempty,oneof=win draw loss over_under corners cards goals first_goal exact_score asian_handicap both_teams_score total_goals half_time_result bet_builder"`
 	Amount       float64 `json:"amount" validate:"omitempty,min=1,valid_amount"`
 	Odds         float64 `json:"odds" validate:"omitempty,min=1,valid_odds"`
-	Status       string  `json:"status" validate:"omitempty,oneof=pending won lost cancelled"`
+	Status       string  `json:"status" validate:"omitempty,oneof=pending won lost half_won half_lost push cancelled void"`
 	Result       string  `json:"result" validate:"omitempty,oneof=win draw loss"`
 	Payout       float64 `json:"payout" validate:"omitempty,min=0"`
 	CashoutValue float64 `json:"cashout_value" validate:"omitempty,min=0"`
@@ -45,22 +56,25 @@ type BetUpdate struct {
 }
 
 type BetResponse struct {
-	ID           uint      `json:"id"`
-	UserID       uint      `json:"user_id"`
-	MatchID      uint      `json:"match_id"`
-	BetType      string    `json:"bet_type"`
-	Amount       float64   `json:"amount"`
-	Odds         float64   `json:"odds"`
-	Status       string    `json:"status"`
-	Result       string    `json:"result"`
-	Payout       float64   `json:"payout"`
-	CashoutValue float64   `json:"cashout_value"`
-	IsCashout    bool      `json:"is_cashout"`
-	BonusApplied float64   `json:"bonus_applied"`
-	PromotionID  *uint     `json:"promotion_id"`
-	BetLimit     float64   `json:"bet_limit"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uint           `json:"id"`
+	UserID       uint           `json:"user_id"`
+	MatchID      uint           `json:"match_id"`
+	BetType      string         `json:"bet_type"`
+	Amount       float64        `json:"amount"`
+	Odds         float64        `json:"odds"`
+	Selection    string         `json:"selection,omitempty"`
+	Line         *float64       `json:"line,omitempty"`
+	Status       string         `json:"status"`
+	Result       string         `json:"result"`
+	Payout       float64        `json:"payout"`
+	CashoutValue float64        `json:"cashout_value"`
+	IsCashout    bool           `json:"is_cashout"`
+	BonusApplied float64        `json:"bonus_applied"`
+	PromotionID  *uint          `json:"promotion_id"`
+	BetLimit     float64        `json:"bet_limit"`
+	Selections   []BetSelection `json:"selections,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
 }
 
 // Tipos de apostas disponíveis
@@ -77,6 +91,21 @@ const (
 	BetTypeAsianHandicap  = "asian_handicap"
 	BetTypeBothTeamsScore = "both_teams_score"
 	BetTypeTotalGoals     = "total_goals"
+	BetTypeHalfTimeResult = "half_time_result"
+	BetTypeBetBuilder     = "bet_builder"
+)
+
+// Palpites (Selection) dos mercados com mais de uma opção: first_goal,
+// half_time_result, asian_handicap (home/away), mercados de linha (over/under)
+// e exact_score, que recebe o placar ("2-1") ou other para os demais placares
+const (
+	SelectionHome       = "home"
+	SelectionDraw       = "draw"
+	SelectionAway       = "away"
+	SelectionNone       = "none"
+	SelectionOver       = "over"
+	SelectionUnder      = "under"
+	SelectionOtherScore = "other"
 )
 
 // Status das apostas
@@ -84,5 +113,9 @@ const (
 	BetStatusPending   = "pending"
 	BetStatusWon       = "won"
 	BetStatusLost      = "lost"
+	BetStatusHalfWon   = "half_won"
+	BetStatusHalfLost  = "half_lost"
+	BetStatusPush      = "push"
 	BetStatusCancelled = "cancelled"
+	BetStatusVoid      = "void"
 )
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+)
+
+// SystemBet é uma aposta de sistema: os palpites, de partidas diferentes, são
+// combinados em todas as combinações dos tamanhos do tipo escolhido, cada uma
+// com o mesmo valor (UnitStake). Cada combinação é liquidada separadamente.
+type SystemBet struct {
+	ID           uint             `json:"id" gorm:"primaryKey"`
+	UserID       uint             `json:"user_id" gorm:"index"`
+	Type         string           `json:"type" validate:"required,oneof=trixie yankee lucky15 custom"`
+	Size         int              `json:"size,omitempty"`
+	UnitStake    float64          `json:"unit_stake"`
+	TotalStake   float64          `json:"total_stake"`
+	Status       string           `json:"status" gorm:"default:'pending'" validate:"oneof=pending won lost void"`
+	Payout       float64          `json:"payout"`
+	SettledAt    *time.Time       `json:"settled_at"`
+	Legs         []SystemBetLeg   `json:"legs" gorm:"foreignKey:SystemBetID"`
+	Combinations []BetCombination `json:"combinations" gorm:"foreignKey:SystemBetID"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// SystemBetLeg é um palpite da aposta de sistema, liquidado quando a partida
+// dele termina
+type SystemBetLeg struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	SystemBetID uint      `json:"system_bet_id" gorm:"index"`
+	Position    int       `json:"position"`
+	MatchID     uint      `json:"match_id" gorm:"index"`
+	BetType     string    `json:"bet_type"`
+	Selection   string    `json:"selection,omitempty"`
+	Line        *float64  `json:"line,omitempty"`
+	Odds        float64   `json:"odds"`
+	Status      string    `json:"status" gorm:"default:'pending'"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// BetCombination é uma das combinações da aposta de sistema. Legs lista as
+// posições dos palpites combinados ("1-2-4"); Odds é a cotação combinada.
+type BetCombination struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	SystemBetID uint      `json:"system_bet_id" gorm:"index"`
+	Legs        string    `json:"legs"`
+	Size        int       `json:"size"`
+	Stake       float64   `json:"stake"`
+	Odds        float64   `json:"odds"`
+	Status      string    `json:"status" gorm:"default:'pending'" validate:"oneof=pending won lost void"`
+	Payout      float64   `json:"payout"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SystemBetSelection é um palpite informado na criação da aposta de sistema
+type SystemBetSelection struct {
+	MatchID   uint     `json:"match_id" validate:"required"`
+	BetType   string   `json:"bet_type" validate:"required,oneof=win draw loss over_under corners cards goals first_goal exact_score asian_handicap both_teams_score total_goals half_time_result"`
+	Selection string   `json:"selection" validate:"omitempty,max=10"`
+	Line      *float64 `json:"line"`
+	Odds      float64  `json:"odds" validate:"required,min=1,valid_odds"`
+}
+
+// SystemBetCreate cria uma aposta de sistema. Size é o tamanho das
+// combinações do tipo custom (N palpites combinados dentre os M informados).
+type SystemBetCreate struct {
+	Type       string               `json:"type" validate:"required,oneof=trixie yankee lucky15 custom"`
+	Size       int                  `json:"size" validate:"omitempty,min=1"`
+	UnitStake  float64              `json:"unit_stake" validate:"required,min=1,valid_amount"`
+	Selections []SystemBetSelection `json:"selections" validate:"required,min=2,max=8,dive"`
+}
+
+// Tipos de apostas de sistema
+const (
+	SystemBetTypeTrixie  = "trixie"
+	SystemBetTypeYankee  = "yankee"
+	SystemBetTypeLucky15 = "lucky15"
+	SystemBetTypeCustom  = "custom"
+)
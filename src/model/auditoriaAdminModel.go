@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+)
+
+// AdminAuditLog registra uma intervenção manual do suporte. Os registros não
+// podem ser alterados nem excluídos (o banco recusa UPDATE e DELETE).
+// Before e After guardam o estado do alvo em JSON.
+type AdminAuditLog struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	AdminID    uint      `json:"admin_id" gorm:"index"`
+	Action     string    `json:"action" validate:"required,oneof=bet_void bet_settle balance_adjustment"`
+	TargetType string    `json:"target_type" validate:"required,oneof=bet user"`
+	TargetID   uint      `json:"target_id"`
+	UserID     uint      `json:"user_id" gorm:"index"`
+	ReasonCode string    `json:"reason_code"`
+	Notes      string    `json:"notes"`
+	Amount     float64   `json:"amount"`
+	Before     string    `json:"before" gorm:"type:text"`
+	After      string    `json:"after" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AdminBetVoid anula uma aposta manualmente
+type AdminBetVoid struct {
+	ReasonCode string `json:"reason_code" validate:"required,oneof=customer_request settlement_error data_correction fraud goodwill technical_issue other"`
+	Notes      string `json:"notes" validate:"omitempty,max=500"`
+}
+
+// AdminBetSettle liquida ou reliquida uma aposta manualmente. Sem Status, a
+// aposta é reliquidada pelos dados atuais da partida.
+type AdminBetSettle struct {
+	Status     string `json:"status" validate:"omitempty,oneof=won lost half_won half_lost push"`
+	ReasonCode string `json:"reason_code" validate:"required,oneof=customer_request settlement_error data_correction fraud goodwill technical_issue other"`
+	Notes      string `json:"notes" validate:"omitempty,max=500"`
+}
+
+// AdminBalanceAdjustment credita (valor positivo) ou debita (negativo) o saldo do usuário
+type AdminBalanceAdjustment struct {
+	Amount     float64 `json:"amount" validate:"required,ne=0"`
+	ReasonCode string  `json:"reason_code" validate:"required,oneof=customer_request settlement_error data_correction fraud goodwill technical_issue other"`
+	Notes      string  `json:"notes" validate:"omitempty,max=500"`
+}
+
+// Ações registradas na auditoria
+const (
+	AuditActionBetVoid           = "bet_void"
+	AuditActionBetSettle         = "bet_settle"
+	AuditActionBalanceAdjustment = "balance_adjustment"
+)
+
+// Motivos das intervenções manuais
+const (
+	ReasonCodeCustomerRequest = "customer_request"
+	ReasonCodeSettlementError = "settlement_error"
+	ReasonCodeDataCorrection  = "data_correction"
+	ReasonCodeFraud           = "fraud"
+	ReasonCodeGoodwill        = "goodwill"
+	ReasonCodeTechnicalIssue  = "technical_issue"
+	ReasonCodeOther           = "other"
+)
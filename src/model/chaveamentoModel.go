@@ -0,0 +1,95 @@
+package model
+
+import (
+	"time"
+)
+
+// BracketTie é um confronto do mata-mata. Pode ser disputado em jogo único
+// ou em ida e volta; o vencedor avança para o confronto indicado em NextTieID.
+type BracketTie struct {
+	ID           uint `json:"id" gorm:"primaryKey"`
+	TournamentID uint `json:"tournament_id" gorm:"index" validate:"required"`
+	// Round é a fase do chaveamento, começando em 1 na primeira fase
+	Round int `json:"round" validate:"min=1"`
+	// Position é a posição do confronto dentro da fase, começando em 0
+	Position      int   `json:"position" validate:"min=0"`
+	HomeTeamID    *uint `json:"home_team_id"`
+	AwayTeamID    *uint `json:"away_team_id"`
+	Legs          int   `json:"legs" gorm:"default:1" validate:"oneof=1 2"`
+	AwayGoalsRule bool  `json:"away_goals_rule"`
+	// Datas previstas das partidas, usadas quando os times do confronto são definidos
+	FirstLegAt    time.Time  `json:"first_leg_at"`
+	SecondLegAt   *time.Time `json:"second_leg_at"`
+	MatchDuration int        `json:"match_duration"`
+	WinnerTeamID  *uint      `json:"winner_team_id"`
+	DecidedBy     string     `json:"decided_by" validate:"omitempty,oneof=bye score aggregate away_goals extra_time penalties admin"`
+	// DecisionReason justifica o vencedor definido pela organização
+	DecisionReason string    `json:"decision_reason,omitempty"`
+	Status         string    `json:"status" gorm:"default:'pending'" validate:"oneof=pending ready decided"`
+	NextTieID      *uint     `json:"next_tie_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// BracketTieResponse é um confronto do chaveamento com suas partidas
+type BracketTieResponse struct {
+	ID             uint            `json:"id"`
+	Round          int             `json:"round"`
+	Position       int             `json:"position"`
+	HomeTeamID     *uint           `json:"home_team_id"`
+	AwayTeamID     *uint           `json:"away_team_id"`
+	Legs           int             `json:"legs"`
+	AwayGoalsRule  bool            `json:"away_goals_rule"`
+	HomeAggregate  int             `json:"home_aggregate"`
+	AwayAggregate  int             `json:"away_aggregate"`
+	WinnerTeamID   *uint           `json:"winner_team_id"`
+	DecidedBy      string          `json:"decided_by,omitempty"`
+	DecisionReason string          `json:"decision_reason,omitempty"`
+	Status         string          `json:"status"`
+	NextTieID      *uint           `json:"next_tie_id"`
+	Matches        []MatchResponse `json:"matches"`
+}
+
+// TieReplay remarca a partida cancelada ou abandonada de um confronto
+type TieReplay struct {
+	StartTime time.Time `json:"start_time" validate:"required,future_date"`
+}
+
+// TieDecision define o vencedor de um confronto que não pôde ser disputado
+// até o fim (partida cancelada ou abandonada)
+type TieDecision struct {
+	WinnerTeamID uint   `json:"winner_team_id" validate:"required"`
+	Reason       string `json:"reason" validate:"required,max=500"`
+}
+
+// BracketRound é uma fase do chaveamento
+type BracketRound struct {
+	Round int                  `json:"round"`
+	Name  string               `json:"name"`
+	Ties  []BracketTieResponse `json:"ties"`
+}
+
+// BracketResponse é a árvore do mata-mata de um torneio, da primeira fase à final
+type BracketResponse struct {
+	TournamentID uint           `json:"tournament_id"`
+	Rounds       []BracketRound `json:"rounds"`
+	ChampionID   *uint          `json:"champion_id"`
+}
+
+// Status dos confrontos do chaveamento
+const (
+	TieStatusPending = "pending"
+	TieStatusReady   = "ready"
+	TieStatusDecided = "decided"
+)
+
+// Critérios que decidiram um confronto
+const (
+	TieDecidedByBye       = "bye"
+	TieDecidedByScore     = "score"
+	TieDecidedByAggregate = "aggregate"
+	TieDecidedByAwayGoals = "away_goals"
+	TieDecidedByExtraTime = "extra_time"
+	TieDecidedByPenalties = "penalties"
+	TieDecidedByAdmin     = "admin"
+)
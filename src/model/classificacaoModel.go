@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+)
+
+// StandingRow é a linha de um time na classificação de um torneio
+type StandingRow struct {
+	Position       int    `json:"position"`
+	TeamID         uint   `json:"team_id"`
+	TeamName       string `json:"team_name"`
+	Played         int    `json:"played"`
+	Wins           int    `json:"wins"`
+	Draws          int    `json:"draws"`
+	Losses         int    `json:"losses"`
+	GoalsFor       int    `json:"goals_for"`
+	GoalsAgainst   int    `json:"goals_against"`
+	GoalDifference int    `json:"goal_difference"`
+	Points         int    `json:"points"`
+	// Form contém os últimos resultados, do mais antigo para o mais recente (W, D, L)
+	Form []string `json:"form"`
+}
+
+// StandingsResponse é a tabela de classificação de um torneio
+type StandingsResponse struct {
+	TournamentID uint          `json:"tournament_id"`
+	PointsWin    int           `json:"points_win"`
+	PointsDraw   int           `json:"points_draw"`
+	PointsLoss   int           `json:"points_loss"`
+	TieBreakers  []string      `json:"tie_breakers"`
+	Table        []StandingRow `json:"table"`
+	GeneratedAt  time.Time     `json:"generated_at"`
+}
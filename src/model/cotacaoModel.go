@@ -0,0 +1,84 @@
+package model
+
+import (
+	"time"
+)
+
+// MarketOdds é uma cotação de um mercado da partida. As cotações sugeridas
+// pelo modelo de precificação nascem como rascunho e só valem depois de
+// aprovadas por um trader.
+type MarketOdds struct {
+	ID      uint   `json:"id" gorm:"primaryKey"`
+	MatchID uint   `json:"match_id" gorm:"index" validate:"required"`
+	Market  string `json:"market" validate:"required,oneof=match_result total_goals both_teams_score exact_score asian_handicap corners cards"`
+	// Selection é a opção do mercado: home, draw, away, over, under, yes, no ou um placar como 2-1
+	Selection string `json:"selection" validate:"required,max=10"`
+	// Line é a linha dos mercados de total de gols (2.5, por exemplo)
+	Line        *float64   `json:"line"`
+	Probability float64    `json:"probability" validate:"min=0,max=1"`
+	FairOdds    float64    `json:"fair_odds" validate:"min=1"`
+	Odds        float64    `json:"odds" validate:"required,min=1"`
+	Margin      float64    `json:"margin" validate:"min=0,max=0.5"`
+	Status      string     `json:"status" gorm:"default:'draft'" validate:"oneof=draft approved rejected superseded"`
+	CreatedBy   uint       `json:"created_by"`
+	ReviewedBy  *uint      `json:"reviewed_by"`
+	ReviewedAt  *time.Time `json:"reviewed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// OddsCompile são as opções para gerar as cotações sugeridas de uma partida
+type OddsCompile struct {
+	// Margin substitui a margem padrão configurada (0.05 = 5%)
+	Margin *float64 `json:"margin" validate:"omitempty,min=0,max=0.5"`
+	DryRun bool     `json:"dry_run"`
+}
+
+// OddsReview aprova ou rejeita cotações em rascunho. Sem IDs, vale para
+// todos os rascunhos da partida. Odds permite ao trader ajustar a cotação
+// de um rascunho antes de aprová-lo.
+type OddsReview struct {
+	IDs  []uint           `json:"ids"`
+	Odds map[uint]float64 `json:"odds" validate:"omitempty,dive,min=1.01"`
+}
+
+// TeamRatingResponse são as forças de ataque e defesa de um time. Valores
+// acima de 1 indicam ataque acima da média ou defesa que sofre mais gols que a média.
+type TeamRatingResponse struct {
+	TeamID  uint    `json:"team_id"`
+	Attack  float64 `json:"attack"`
+	Defence float64 `json:"defence"`
+	Matches int     `json:"matches"`
+}
+
+// OddsCompileResponse é o resultado da precificação de uma partida
+type OddsCompileResponse struct {
+	MatchID           uint               `json:"match_id"`
+	HomeExpectedGoals float64            `json:"home_expected_goals"`
+	AwayExpectedGoals float64            `json:"away_expected_goals"`
+	Home              TeamRatingResponse `json:"home"`
+	Away              TeamRatingResponse `json:"away"`
+	MatchesUsed       int                `json:"matches_used"`
+	Margin            float64            `json:"margin"`
+	DryRun            bool               `json:"dry_run"`
+	Odds              []MarketOdds       `json:"odds"`
+}
+
+// Mercados precificados
+const (
+	MarketMatchResult    = "match_result"
+	MarketTotalGoals     = "total_goals"
+	MarketBothTeamsScore = "both_teams_score"
+	MarketExactScore     = "exact_score"
+	MarketAsianHandicap  = "asian_handicap"
+	MarketCorners        = "corners"
+	MarketCards          = "cards"
+)
+
+// Status das cotações
+const (
+	OddsStatusDraft      = "draft"
+	OddsStatusApproved   = "approved"
+	OddsStatusRejected   = "rejected"
+	OddsStatusSuperseded = "superseded"
+)
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+)
+
+// BetSelection é um dos palpites de uma aposta combinada do criador de
+// apostas (bet builder). A aposta só é ganha se todos os palpites acertarem.
+type BetSelection struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	BetID     uint      `json:"bet_id" gorm:"index"`
+	BetType   string    `json:"bet_type"`
+	Selection string    `json:"selection,omitempty"`
+	Line      *float64  `json:"line,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BetBuilderSelection é um palpite informado no criador de apostas
+type BetBuilderSelection struct {
+	BetType   string   `json:"bet_type" validate:"required,oneof=win draw loss both_teams_score over_under total_goals asian_handicap"`
+	Selection string   `json:"selection" validate:"omitempty,oneof=home away over under"`
+	Line      *float64 `json:"line"`
+}
+
+// BetBuilderQuote pede a cotação de uma combinação de palpites da mesma partida
+type BetBuilderQuote struct {
+	MatchID    uint                  `json:"match_id" validate:"required"`
+	Selections []BetBuilderSelection `json:"selections" validate:"required,min=2,max=6,dive"`
+}
+
+// BetBuilderCreate cria uma aposta combinada. Odds é a cotação aceita pelo
+// usuário; se a cotação atual for menor, a aposta é recusada. Sem Odds vale
+// a cotação atual.
+type BetBuilderCreate struct {
+	MatchID    uint                  `json:"match_id" validate:"required"`
+	Amount     float64               `json:"amount" validate:"required,min=1,valid_amount"`
+	Odds       float64               `json:"odds" validate:"omitempty,min=1,valid_odds"`
+	Selections []BetBuilderSelection `json:"selections" validate:"required,min=2,max=6,dive"`
+}
+
+// BetBuilderQuoteResponse é a cotação de uma combinação, calculada pela
+// probabilidade conjunta na matriz de placares do modelo de precificação
+type BetBuilderQuoteResponse struct {
+	MatchID     uint                  `json:"match_id"`
+	Selections  []BetBuilderSelection `json:"selections"`
+	Probability float64               `json:"probability"`
+	FairOdds    float64               `json:"fair_odds"`
+	Odds        float64               `json:"odds"`
+	Margin      float64               `json:"margin"`
+	// NaiveOdds é o produto das cotações justas de cada palpite, para comparação
+	NaiveOdds float64 `json:"naive_odds"`
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+)
+
+// MatchLineupPlayer é um jogador relacionado para uma partida, como titular ou reserva
+type MatchLineupPlayer struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	MatchID     uint   `json:"match_id" gorm:"index" validate:"required"`
+	TeamID      uint   `json:"team_id" validate:"required"`
+	PlayerID    uint   `json:"player_id" validate:"required"`
+	ShirtNumber int    `json:"shirt_number" validate:"required,min=1,max=99"`
+	Role        string `json:"role" validate:"required,oneof=starter bench"`
+	// Position é a posição em que o jogador atua nesta partida
+	Position  string `json:"position" validate:"required,oneof=goalkeeper defender midfielder forward"`
+	IsCaptain bool   `json:"is_captain"`
+	// Minutos de entrada e saída registrados pelos eventos de substituição
+	SubbedInMinute  *int      `json:"subbed_in_minute"`
+	SubbedOutMinute *int      `json:"subbed_out_minute"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// LineupPlayerInput é um jogador informado na escalação
+type LineupPlayerInput struct {
+	PlayerID    uint   `json:"player_id" validate:"required"`
+	ShirtNumber int    `json:"shirt_number" validate:"required,min=1,max=99"`
+	Position    string `json:"position" validate:"omitempty,oneof=goalkeeper defender midfielder forward"`
+	IsCaptain   bool   `json:"is_captain"`
+}
+
+// LineupSubmit contém a escalação completa de um time para a partida
+type LineupSubmit struct {
+	TeamID    uint                `json:"team_id" validate:"required"`
+	Formation string              `json:"formation" validate:"required,oneof=4-4-2 4-3-3 4-2-3-1 3-5-2 5-3-2 4-5-1"`
+	Starters  []LineupPlayerInput `json:"starters" validate:"required,len=11,dive"`
+	Bench     []LineupPlayerInput `json:"bench" validate:"omitempty,max=12,dive"`
+}
+
+// LineupPlayerResponse é um jogador da escalação
+type LineupPlayerResponse struct {
+	PlayerID        uint   `json:"player_id"`
+	Name            string `json:"name"`
+	ShirtNumber     int    `json:"shirt_number"`
+	Position        string `json:"position"`
+	Role            string `json:"role"`
+	IsCaptain       bool   `json:"is_captain"`
+	SubbedInMinute  *int   `json:"subbed_in_minute,omitempty"`
+	SubbedOutMinute *int   `json:"subbed_out_minute,omitempty"`
+}
+
+// LineupResponse é a escalação de um time na partida
+type LineupResponse struct {
+	MatchID   uint                   `json:"match_id"`
+	TeamID    uint                   `json:"team_id"`
+	IsHome    bool                   `json:"is_home"`
+	Formation string                 `json:"formation"`
+	Starters  []LineupPlayerResponse `json:"starters"`
+	Bench     []LineupPlayerResponse `json:"bench"`
+}
+
+// Funções dos jogadores na escalação
+const (
+	LineupRoleStarter = "starter"
+	LineupRoleBench   = "bench"
+)
+
+// Posições dos jogadores
+const (
+	PositionGoalkeeper = "goalkeeper"
+	PositionDefender   = "defender"
+	PositionMidfielder = "midfielder"
+	PositionForward    = "forward"
+)
@@ -0,0 +1,44 @@
+package model
+
+// PlayerStatsResponse reúne as estatísticas de um jogador nas partidas encerradas
+type PlayerStatsResponse struct {
+	PlayerID     uint   `json:"player_id"`
+	Name         string `json:"name"`
+	TeamID       uint   `json:"team_id"`
+	TournamentID *uint  `json:"tournament_id,omitempty"`
+	StartDate    string `json:"start_date,omitempty"`
+	EndDate      string `json:"end_date,omitempty"`
+	// Partidas em que o jogador entrou em campo
+	Appearances           int `json:"appearances"`
+	Starts                int `json:"starts"`
+	SubstituteAppearances int `json:"substitute_appearances"`
+	MinutesPlayed         int `json:"minutes_played"`
+	// Goals não inclui gols contra, que aparecem apenas em GoalsByType
+	Goals       int            `json:"goals"`
+	GoalsByType map[string]int `json:"goals_by_type"`
+	Cards       map[string]int `json:"cards"`
+	SubbedIn    int            `json:"subbed_in"`
+	SubbedOut   int            `json:"subbed_out"`
+}
+
+// TopScorerRow é uma linha da artilharia de um torneio
+type TopScorerRow struct {
+	Position   int    `json:"position"`
+	PlayerID   uint   `json:"player_id"`
+	PlayerName string `json:"player_name"`
+	TeamID     uint   `json:"team_id"`
+	Goals      int    `json:"goals"`
+	Penalties  int    `json:"penalties"`
+}
+
+// DisciplineRow é uma linha do ranking disciplinar de um torneio
+type DisciplineRow struct {
+	Position    int    `json:"position"`
+	PlayerID    uint   `json:"player_id"`
+	PlayerName  string `json:"player_name"`
+	TeamID      uint   `json:"team_id"`
+	YellowCards int    `json:"yellow_cards"`
+	RedCards    int    `json:"red_cards"`
+	// Points pondera os cartões: amarelo vale 1 e vermelho vale 3
+	Points int `json:"points"`
+}
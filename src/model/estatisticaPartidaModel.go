@@ -63,29 +63,31 @@ type MatchStatisticsCreate struct {
 	AwaySaves         int     `json:"away_saves" validate:"min=0"`
 }
 
+// MatchStatisticsUpdate usa ponteiros para que um campo possa ser corrigido
+// para zero; campos ausentes não são alterados
 type MatchStatisticsUpdate struct {
-	HomePossession    float64 `json:"home_possession" validate:"omitempty,min=0,max=100"`
-	AwayPossession    float64 `json:"away_possession" validate:"omitempty,min=0,max=100"`
-	HomeShots         int     `json:"home_shots" validate:"omitempty,min=0"`
-	AwayShots         int     `json:"away_shots" validate:"omitempty,min=0"`
-	HomeShotsOnTarget int     `json:"home_shots_on_target" validate:"omitempty,min=0"`
-	AwayShotsOnTarget int     `json:"away_shots_on_target" validate:"omitempty,min=0"`
-	HomeCorners       int     `json:"home_corners" validate:"omitempty,min=0"`
-	AwayCorners       int     `json:"away_corners" validate:"omitempty,min=0"`
-	HomeFouls         int     `json:"home_fouls" validate:"omitempty,min=0"`
-	AwayFouls         int     `json:"away_fouls" validate:"omitempty,min=0"`
-	HomeYellowCards   int     `json:"home_yellow_cards" validate:"omitempty,min=0"`
-	AwayYellowCards   int     `json:"away_yellow_cards" validate:"omitempty,min=0"`
-	HomeRedCards      int     `json:"home_red_cards" validate:"omitempty,min=0"`
-	AwayRedCards      int     `json:"away_red_cards" validate:"omitempty,min=0"`
-	HomeOffsides      int     `json:"home_offsides" validate:"omitempty,min=0"`
-	AwayOffsides      int     `json:"away_offsides" validate:"omitempty,min=0"`
-	HomePasses        int     `json:"home_passes" validate:"omitempty,min=0"`
-	AwayPasses        int     `json:"away_passes" validate:"omitempty,min=0"`
-	HomePassAccuracy  float64 `json:"home_pass_accuracy" validate:"omitempty,min=0,max=100"`
-	AwayPassAccuracy  float64 `json:"away_pass_accuracy" validate:"omitempty,min=0,max=100"`
-	HomeSaves         int     `json:"home_saves" validate:"omitempty,min=0"`
-	AwaySaves         int     `json:"away_saves" validate:"omitempty,min=0"`
+	HomePossession    *float64 `json:"home_possession" validate:"omitempty,min=0,max=100"`
+	AwayPossession    *float64 `json:"away_possession" validate:"omitempty,min=0,max=100"`
+	HomeShots         *int     `json:"home_shots" validate:"omitempty,min=0"`
+	AwayShots         *int     `json:"away_shots" validate:"omitempty,min=0"`
+	HomeShotsOnTarget *int     `json:"home_shots_on_target" validate:"omitempty,min=0"`
+	AwayShotsOnTarget *int     `json:"away_shots_on_target" validate:"omitempty,min=0"`
+	HomeCorners       *int     `json:"home_corners" validate:"omitempty,min=0"`
+	AwayCorners       *int     `json:"away_corners" validate:"omitempty,min=0"`
+	HomeFouls         *int     `json:"home_fouls" validate:"omitempty,min=0"`
+	AwayFouls         *int     `json:"away_fouls" validate:"omitempty,min=0"`
+	HomeYellowCards   *int     `json:"home_yellow_cards" validate:"omitempty,min=0"`
+	AwayYellowCards   *int     `json:"away_yellow_cards" validate:"omitempty,min=0"`
+	HomeRedCards      *int     `json:"home_red_cards" validate:"omitempty,min=0"`
+	AwayRedCards      *int     `json:"away_red_cards" validate:"omitempty,min=0"`
+	HomeOffsides      *int     `json:"home_offsides" validate:"omitempty,min=0"`
+	AwayOffsides      *int     `json:"away_offsides" validate:"omitempty,min=0"`
+	HomePasses        *int     `json:"home_passes" validate:"omitempty,min=0"`
+	AwayPasses        *int     `json:"away_passes" validate:"omitempty,min=0"`
+	HomePassAccuracy  *float64 `json:"home_pass_accuracy" validate:"omitempty,min=0,max=100"`
+	AwayPassAccuracy  *float64 `json:"away_pass_accuracy" validate:"omitempty,min=0,max=100"`
+	HomeSaves         *int     `json:"home_saves" validate:"omitempty,min=0"`
+	AwaySaves         *int     `json:"away_saves" validate:"omitempty,min=0"`
 }
 
 type MatchStatisticsResponse struct {
@@ -5,12 +5,15 @@ import (
 )
 
 type MatchEvent struct {
-	ID          uint   `json:"id" gorm:"primaryKey"`
-	MatchID     uint   `json:"match_id" validate:"required"`
-	EventType   string `json:"event_type" validate:"required,oneof=goal card foul substitution throw_in corner"`
-	TeamID      uint   `json:"team_id" validate:"required"`
-	PlayerID    uint   `json:"player_id" validate:"required"`
-	Minute      int    `json:"minute" validate:"required,min=1,max=120"`
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	MatchID   uint   `json:"match_id" validate:"required"`
+	EventType string `json:"event_type" validate:"required,oneof=goal card foul substitution throw_in corner"`
+	TeamID    uint   `json:"team_id" validate:"required"`
+	PlayerID  uint   `json:"player_id" validate:"required"`
+	Minute    int    `json:"minute" validate:"required,min=1,max=120"`
+	// Period indica o tempo de jogo e AddedMinute os acréscimos (45+3 é Minute 45 e AddedMinute 3)
+	Period      string `json:"period" gorm:"not null;default:'first_half'" validate:"omitempty,oneof=first_half second_half extra_time_first extra_time_second penalties"`
+	AddedMinute int    `json:"added_minute" gorm:"not null;default:0" validate:"omitempty,min=0,max=30"`
 	Description string `json:"description" validate:"required,min=3,max=500"`
 	// Campos específicos para cada tipo de evento
 	GoalType       string    `json:"goal_type,omitempty" validate:"omitempty,oneof=normal penalty own_goal"`
@@ -30,6 +33,8 @@ type MatchEventCreate struct {
 	TeamID         uint   `json:"team_id" validate:"required"`
 	PlayerID       uint   `json:"player_id" validate:"required"`
 	Minute         int    `json:"minute" validate:"required,min=1,max=120"`
+	Period         string `json:"period,omitempty" validate:"omitempty,oneof=first_half second_half extra_time_first extra_time_second penalties"`
+	AddedMinute    int    `json:"added_minute,omitempty" validate:"omitempty,min=0,max=30"`
 	Description    string `json:"description" validate:"required,min=3,max=500"`
 	GoalType       string `json:"goal_type,omitempty" validate:"omitempty,oneof=normal penalty own_goal"`
 	CardType       string `json:"card_type,omitempty" validate:"omitempty,oneof=yellow red"`
@@ -38,6 +43,7 @@ type MatchEventCreate struct {
 	SubOutPlayerID uint   `json:"sub_out_player_id,omitempty" validate:"omitempty"`
 	ThrowInSide    string `json:"throw_in_side,omitempty" validate:"omitempty,oneof=left right"`
 	CornerSide     string `json:"corner_side,omitempty" validate:"omitempty,oneof=left right"`
+	Reason         string `json:"reason,omitempty" validate:"omitempty,max=500"`
 }
 
 type MatchEventUpdate struct {
@@ -45,6 +51,8 @@ type MatchEventUpdate struct {
 	TeamID         uint   `json:"team_id,omitempty" validate:"omitempty"`
 	PlayerID       uint   `json:"player_id,omitempty" validate:"omitempty"`
 	Minute         int    `json:"minute,omitempty" validate:"omitempty,min=1,max=120"`
+	Period         string `json:"period,omitempty" validate:"omitempty,oneof=first_half second_half extra_time_first extra_time_second penalties"`
+	AddedMinute    *int   `json:"added_minute,omitempty" validate:"omitempty,min=0,max=30"`
 	Description    string `json:"description,omitempty" validate:"omitempty,min=3,max=500"`
 	GoalType       string `json:"goal_type,omitempty" validate:"omitempty,oneof=normal penalty own_goal"`
 	CardType       string `json:"card_type,omitempty" validate:"omitempty,oneof=yellow red"`
@@ -53,6 +61,8 @@ type MatchEventUpdate struct {
 	SubOutPlayerID uint   `json:"sub_out_player_id,omitempty" validate:"omitempty"`
 	ThrowInSide    string `json:"throw_in_side,omitempty" validate:"omitempty,oneof=left right"`
 	CornerSide     string `json:"corner_side,omitempty" validate:"omitempty,oneof=left right"`
+	// Reason é obrigatório: toda alteração fica registrada no histórico do evento
+	Reason string `json:"reason" validate:"required,min=3,max=500"`
 }
 
 type MatchEventResponse struct {
@@ -62,6 +72,8 @@ type MatchEventResponse struct {
 	TeamID         uint      `json:"team_id"`
 	PlayerID       uint      `json:"player_id"`
 	Minute         int       `json:"minute"`
+	Period         string    `json:"period"`
+	AddedMinute    int       `json:"added_minute"`
 	Description    string    `json:"description"`
 	GoalType       string    `json:"goal_type,omitempty"`
 	CardType       string    `json:"card_type,omitempty"`
@@ -84,6 +96,15 @@ const (
 	EventTypeCorner       = "corner"
 )
 
+// Períodos da partida
+const (
+	PeriodFirstHalf       = "first_half"
+	PeriodSecondHalf      = "second_half"
+	PeriodExtraTimeFirst  = "extra_time_first"
+	PeriodExtraTimeSecond = "extra_time_second"
+	PeriodPenalties       = "penalties"
+)
+
 // Tipos de gols
 const (
 	GoalTypeNormal  = "normal"
@@ -103,3 +124,37 @@ const (
 	FoulTypeDangerous = "dangerous"
 	FoulTypeSerious   = "serious"
 )
+
+// TimelineEntry é um evento da linha do tempo da partida, já com nomes e placar
+type TimelineEntry struct {
+	EventID     uint   `json:"event_id"`
+	Period      string `json:"period"`
+	Minute      int    `json:"minute"`
+	AddedMinute int    `json:"added_minute"`
+	// Clock é o minuto exibido, como 45+3'
+	Clock            string `json:"clock"`
+	EventType        string `json:"event_type"`
+	TeamID           uint   `json:"team_id"`
+	TeamName         string `json:"team_name"`
+	PlayerID         uint   `json:"player_id"`
+	PlayerName       string `json:"player_name"`
+	SubInPlayerID    uint   `json:"sub_in_player_id,omitempty"`
+	SubInPlayerName  string `json:"sub_in_player_name,omitempty"`
+	SubOutPlayerID   uint   `json:"sub_out_player_id,omitempty"`
+	SubOutPlayerName string `json:"sub_out_player_name,omitempty"`
+	Text             string `json:"text"`
+	// Placar da partida depois do evento; gols da disputa de pênaltis não entram
+	HomeScore int `json:"home_score"`
+	AwayScore int `json:"away_score"`
+}
+
+// TimelineResponse é a linha do tempo completa de uma partida
+type TimelineResponse struct {
+	MatchID      uint            `json:"match_id"`
+	HomeTeamID   uint            `json:"home_team_id"`
+	HomeTeamName string          `json:"home_team_name"`
+	AwayTeamID   uint            `json:"away_team_id"`
+	AwayTeamName string          `json:"away_team_name"`
+	Status       string          `json:"status"`
+	Events       []TimelineEntry `json:"events"`
+}
@@ -31,6 +31,7 @@ type PlayerCreate struct {
 }
 
 type PlayerUpdate struct {
+	// TeamID só é aceito se for o time atual; trocas de time usam as transferências
 	TeamID      uint      `json:"team_id" validate:"omitempty"`
 	Name        string    `json:"name" validate:"omitempty,min=3,max=100"`
 	Number      int       `json:"number" validate:"omitempty,min=1,max=99"`
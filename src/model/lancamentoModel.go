@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+)
+
+// LedgerEntry é um lançamento no extrato do usuário. Toda alteração de saldo
+// gera um lançamento: valores positivos creditam e negativos debitam.
+type LedgerEntry struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"index" validate:"required"`
+	BetID       *uint     `json:"bet_id" gorm:"index"`
+	Type        string    `json:"type" validate:"required,oneof=bet_stake bet_payout bet_refund correction manual_adjustment"`
+	Amount      float64   `json:"amount"`
+	Description string    `json:"description" validate:"required,max=500"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Tipos de lançamentos
+const (
+	LedgerTypeBetStake   = "bet_stake"
+	LedgerTypeBetPayout  = "bet_payout"
+	LedgerTypeBetRefund  = "bet_refund"
+	LedgerTypeCorrection = "correction"
+	// LedgerTypeManualAdjustment é um ajuste de saldo feito pelo suporte
+	LedgerTypeManualAdjustment = "manual_adjustment"
+)
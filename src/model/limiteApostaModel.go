@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// StakeLimit define o valor máximo de aposta e de prêmio. Sem torneio e sem
+// mercado o limite é global; com torneio e/ou mercado vale apenas para as
+// apostas correspondentes. Valores zero não limitam.
+type StakeLimit struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	TournamentID *uint     `json:"tournament_id" gorm:"index"`
+	Market       string    `json:"market" validate:"omitempty,max=50"`
+	MaxStake     float64   `json:"max_stake" validate:"min=0"`
+	MaxPayout    float64   `json:"max_payout" validate:"min=0"`
+	Description  string    `json:"description" validate:"omitempty,max=255"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// StakeLimitInput cria ou altera um limite de aposta
+type StakeLimitInput struct {
+	TournamentID *uint   `json:"tournament_id"`
+	Market       string  `json:"market" validate:"omitempty,oneof=match_result both_teams_score first_goal half_time_result over_under corners cards goals exact_score asian_handicap total_goals bet_builder"`
+	MaxStake     float64 `json:"max_stake" validate:"min=0"`
+	MaxPayout    float64 `json:"max_payout" validate:"min=0"`
+	Description  string  `json:"description" validate:"omitempty,max=255"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+)
+
+type Notification struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"index" validate:"required"`
+	Type      string    `json:"type" validate:"required,oneof=bet_voided bet_settled balance_adjusted"`
+	Title     string    `json:"title" validate:"required,max=100"`
+	Message   string    `json:"message" validate:"required,max=500"`
+	IsRead    bool      `json:"is_read"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type NotificationResponse struct {
+	ID        uint      `json:"id"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	IsRead    bool      `json:"is_read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Tipos de notificações
+const (
+	NotificationTypeBetVoided       = "bet_voided"
+	NotificationTypeBetSettled      = "bet_settled"
+	NotificationTypeBalanceAdjusted = "balance_adjusted"
+)
@@ -7,19 +7,30 @@ import (
 type Match struct {
 	ID           uint      `json:"id" gorm:"primaryKey"`
 	TournamentID uint      `json:"tournament_id" validate:"required"`
+	Round        int       `json:"round" validate:"min=0"`
+	BracketTieID *uint     `json:"bracket_tie_id" gorm:"index"`
+	Leg          int       `json:"leg" validate:"min=0,max=2"`
 	HomeTeamID   uint      `json:"home_team_id" validate:"required"`
 	AwayTeamID   uint      `json:"away_team_id" validate:"required"`
 	StartTime    time.Time `json:"start_time" validate:"required,future_date"`
 	EndTime      time.Time `json:"end_time" validate:"required,future_date"`
-	Status       string    `json:"status" validate:"required,oneof=scheduled live finished cancelled postponed"`
+	Status       string    `json:"status" validate:"required,oneof=scheduled live half_time finished postponed cancelled abandoned"`
+	StatusReason string    `json:"status_reason" validate:"omitempty,max=255"`
+	MarketStatus string    `json:"market_status" gorm:"default:'open'" validate:"omitempty,oneof=open suspended closed"`
 	HomeScore    int       `json:"home_score" validate:"min=0,valid_score"`
 	AwayScore    int       `json:"away_score" validate:"min=0,valid_score"`
-	Stadium      string    `json:"stadium" validate:"required,min=3,max=100"`
-	Referee      string    `json:"referee" validate:"required,min=3,max=100"`
-	Attendance   int       `json:"attendance" validate:"min=0"`
-	Weather      string    `json:"weather" validate:"omitempty,oneof=sunny cloudy rainy snowy windy"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	// Gols marcados na prorrogação e cobranças convertidas na disputa de
+	// pênaltis. HomeScore e AwayScore guardam apenas o tempo regulamentar.
+	HomeExtraTimeScore *int      `json:"home_extra_time_score" validate:"omitempty,min=0"`
+	AwayExtraTimeScore *int      `json:"away_extra_time_score" validate:"omitempty,min=0"`
+	HomePenalties      *int      `json:"home_penalties" validate:"omitempty,min=0"`
+	AwayPenalties      *int      `json:"away_penalties" validate:"omitempty,min=0"`
+	Stadium            string    `json:"stadium" validate:"required,min=3,max=100"`
+	Referee            string    `json:"referee" validate:"required,min=3,max=100"`
+	Attendance         int       `json:"attendance" validate:"min=0"`
+	Weather            string    `json:"weather" validate:"omitempty,oneof=sunny cloudy rainy snowy windy"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
 }
 
 type MatchCreate struct {
@@ -39,9 +50,9 @@ type MatchUpdate struct {
 	AwayTeamID   uint      `json:"away_team_id" validate:"omitempty"`
 	StartTime    time.Time `json:"start_time" validate:"omitempty,future_date"`
 	EndTime      time.Time `json:"end_time" validate:"omitempty,future_date"`
-	Status       string    `json:"status" validate:"omitempty,oneof=scheduled live finished cancelled postponed"`
-	HomeScore    int       `json:"home_score" validate:"omitempty,min=0,valid_score"`
-	AwayScore    int       `json:"away_score" validate:"omitempty,min=0,valid_score"`
+	Status       string    `json:"status" validate:"omitempty,oneof=scheduled live half_time finished postponed cancelled abandoned"`
+	HomeScore    *int      `json:"home_score" validate:"omitempty,min=0"`
+	AwayScore    *int      `json:"away_score" validate:"omitempty,min=0"`
 	Stadium      string    `json:"stadium" validate:"omitempty,min=3,max=100"`
 	Referee      string    `json:"referee" validate:"omitempty,min=3,max=100"`
 	Attendance   int       `json:"attendance" validate:"omitempty,min=0"`
@@ -49,19 +60,68 @@ type MatchUpdate struct {
 }
 
 type MatchResponse struct {
-	ID           uint      `json:"id"`
-	TournamentID uint      `json:"tournament_id"`
-	HomeTeamID   uint      `json:"home_team_id"`
-	AwayTeamID   uint      `json:"away_team_id"`
-	StartTime    time.Time `json:"start_time"`
-	EndTime      time.Time `json:"end_time"`
-	Status       string    `json:"status"`
-	HomeScore    int       `json:"home_score"`
-	AwayScore    int       `json:"away_score"`
-	Stadium      string    `json:"stadium"`
-	Referee      string    `json:"referee"`
-	Attendance   int       `json:"attendance"`
-	Weather      string    `json:"weather"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                 uint      `json:"id"`
+	TournamentID       uint      `json:"tournament_id"`
+	Round              int       `json:"round,omitempty"`
+	BracketTieID       *uint     `json:"bracket_tie_id,omitempty"`
+	Leg                int       `json:"leg,omitempty"`
+	HomeTeamID         uint      `json:"home_team_id"`
+	AwayTeamID         uint      `json:"away_team_id"`
+	StartTime          time.Time `json:"start_time"`
+	EndTime            time.Time `json:"end_time"`
+	Status             string    `json:"status"`
+	StatusReason       string    `json:"status_reason,omitempty"`
+	MarketStatus       string    `json:"market_status"`
+	HomeScore          int       `json:"home_score"`
+	AwayScore          int       `json:"away_score"`
+	HomeExtraTimeScore *int      `json:"home_extra_time_score,omitempty"`
+	AwayExtraTimeScore *int      `json:"away_extra_time_score,omitempty"`
+	HomePenalties      *int      `json:"home_penalties,omitempty"`
+	AwayPenalties      *int      `json:"away_penalties,omitempty"`
+	Stadium            string    `json:"stadium"`
+	Referee            string    `json:"referee"`
+	Attendance         int       `json:"attendance"`
+	Weather            string    `json:"weather"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// MatchFinish contém o placar final informado ao encerrar a partida. Nas
+// partidas de mata-mata empatadas, informa também prorrogação e pênaltis.
+type MatchFinish struct {
+	HomeScore          *int `json:"home_score" validate:"omitempty,min=0"`
+	AwayScore          *int `json:"away_score" validate:"omitempty,min=0"`
+	HomeExtraTimeScore *int `json:"home_extra_time_score" validate:"omitempty,min=0"`
+	AwayExtraTimeScore *int `json:"away_extra_time_score" validate:"omitempty,min=0"`
+	HomePenalties      *int `json:"home_penalties" validate:"omitempty,min=0"`
+	AwayPenalties      *int `json:"away_penalties" validate:"omitempty,min=0"`
+}
+
+// MatchStatusChange contém o motivo de adiamento, cancelamento ou abandono
+type MatchStatusChange struct {
+	Reason string `json:"reason" validate:"required,min=3,max=255"`
 }
+
+// MatchReschedule contém as novas datas de uma partida adiada
+type MatchReschedule struct {
+	StartTime time.Time `json:"start_time" validate:"required,future_date"`
+	EndTime   time.Time `json:"end_time" validate:"required,future_date"`
+}
+
+// Status das partidas
+const (
+	MatchStatusScheduled = "scheduled"
+	MatchStatusLive      = "live"
+	MatchStatusHalfTime  = "half_time"
+	MatchStatusFinished  = "finished"
+	MatchStatusPostponed = "postponed"
+	MatchStatusCancelled = "cancelled"
+	MatchStatusAbandoned = "abandoned"
+)
+
+// Status dos mercados de apostas de uma partida
+const (
+	MarketStatusOpen      = "open"
+	MarketStatusSuspended = "suspended"
+	MarketStatusClosed    = "closed"
+)
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+)
+
+// FinancialReportRow é o resultado da casa em um período e grupo. GGR (gross
+// gaming revenue) é o valor apostado menos os prêmios pagos; NGR desconta o
+// custo dos bônus. Hold é o GGR em percentual do valor apostado.
+type FinancialReportRow struct {
+	Period     time.Time `json:"period"`
+	GroupKey   string    `json:"group_key,omitempty"`
+	GroupLabel string    `json:"group_label,omitempty"`
+	Bets       int       `json:"bets"`
+	Stakes     float64   `json:"stakes"`
+	Payouts    float64   `json:"payouts"`
+	GGR        float64   `json:"ggr"`
+	BonusCost  float64   `json:"bonus_cost"`
+	NGR        float64   `json:"ngr"`
+	Hold       float64   `json:"hold_percentage"`
+}
+
+// FinancialReportTotals soma as linhas do relatório
+type FinancialReportTotals struct {
+	Bets      int     `json:"bets"`
+	Stakes    float64 `json:"stakes"`
+	Payouts   float64 `json:"payouts"`
+	GGR       float64 `json:"ggr"`
+	BonusCost float64 `json:"bonus_cost"`
+	NGR       float64 `json:"ngr"`
+	Hold      float64 `json:"hold_percentage"`
+}
+
+// FinancialReportResponse é o relatório financeiro das apostas liquidadas no período
+type FinancialReportResponse struct {
+	Interval  string                `json:"interval"`
+	GroupBy   string                `json:"group_by,omitempty"`
+	StartDate *time.Time            `json:"start_date,omitempty"`
+	EndDate   *time.Time            `json:"end_date,omitempty"`
+	Rows      []FinancialReportRow  `json:"rows"`
+	Totals    FinancialReportTotals `json:"totals"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// MatchEventRevision registra cada alteração de um evento da partida, com o
+// estado anterior e o novo em JSON, quem alterou e o motivo
+type MatchEventRevision struct {
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	EventID   uint   `json:"event_id" gorm:"index" validate:"required"`
+	MatchID   uint   `json:"match_id" gorm:"index" validate:"required"`
+	Action    string `json:"action" validate:"required,oneof=created updated deleted"`
+	Before    string `json:"before,omitempty" gorm:"type:text"`
+	After     string `json:"after,omitempty" gorm:"type:text"`
+	Reason    string `json:"reason" validate:"omitempty,max=500"`
+	ChangedBy uint   `json:"changed_by"`
+	// BetsResettled é a quantidade de apostas reliquidadas pela alteração
+	BetsResettled int       `json:"bets_resettled"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Ações registradas nas revisões
+const (
+	RevisionActionCreated = "created"
+	RevisionActionUpdated = "updated"
+	RevisionActionDeleted = "deleted"
+)
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+)
+
+// RiskPosition é a exposição da casa em uma opção de mercado da partida,
+// calculada a partir das apostas pendentes. Liability é quanto a casa perde
+// se a opção vencer: os prêmios da opção menos tudo o que foi apostado no mercado.
+type RiskPosition struct {
+	ID        uint    `json:"id" gorm:"primaryKey"`
+	MatchID   uint    `json:"match_id" gorm:"index" validate:"required"`
+	Market    string  `json:"market" validate:"required"`
+	Selection string  `json:"selection"`
+	BetCount  int     `json:"bet_count"`
+	Stakes    float64 `json:"stakes"`
+	Payouts   float64 `json:"payouts"`
+	Liability float64 `json:"liability"`
+	// Level é quantas faixas de responsabilidade a opção já ultrapassou
+	Level int `json:"level"`
+	// OddsFactor reduz as cotações aprovadas da opção (1 = sem ajuste)
+	OddsFactor float64 `json:"odds_factor" gorm:"not null;default:1"`
+	// MaxStake é o maior valor aceito por aposta na opção (0 = sem limite de risco)
+	MaxStake  float64   `json:"max_stake"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RiskAlert é um alerta gerado quando a exposição de uma opção passa do limite
+type RiskAlert struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	MatchID        uint       `json:"match_id" gorm:"index" validate:"required"`
+	Market         string     `json:"market" validate:"required"`
+	Selection      string     `json:"selection"`
+	Liability      float64    `json:"liability"`
+	LiabilityLimit float64    `json:"liability_limit"`
+	Status         string     `json:"status" gorm:"default:'open'" validate:"oneof=open acknowledged"`
+	AcknowledgedBy *uint      `json:"acknowledged_by"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// RiskMatchResponse é a visão de risco de uma partida
+type RiskMatchResponse struct {
+	MatchID      uint           `json:"match_id"`
+	Status       string         `json:"status"`
+	MarketStatus string         `json:"market_status"`
+	Positions    []RiskPosition `json:"positions"`
+	// WorstCase é a maior responsabilidade entre as opções da partida
+	WorstCase     float64     `json:"worst_case"`
+	LiabilityStep float64     `json:"liability_step"`
+	Limit         float64     `json:"limit"`
+	Alerts        []RiskAlert `json:"alerts"`
+}
+
+// Status dos alertas de risco
+const (
+	RiskAlertStatusOpen         = "open"
+	RiskAlertStatusAcknowledged = "acknowledged"
+)
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+)
+
+// PlayerSuspension é uma suspensão de um jogador em um torneio, aplicada a
+// partir de um cartão. Cada partida encerrada do time reduz MatchesRemaining.
+type PlayerSuspension struct {
+	ID           uint `json:"id" gorm:"primaryKey"`
+	PlayerID     uint `json:"player_id" gorm:"index" validate:"required"`
+	TeamID       uint `json:"team_id" validate:"required"`
+	TournamentID uint `json:"tournament_id" gorm:"index" validate:"required"`
+	// MatchID e EventID indicam a partida e o cartão que geraram a suspensão
+	MatchID          uint      `json:"match_id" validate:"required"`
+	EventID          uint      `json:"event_id" validate:"required"`
+	Reason           string    `json:"reason" validate:"required,oneof=red_card second_yellow yellow_accumulation"`
+	MatchesTotal     int       `json:"matches_total" validate:"min=1"`
+	MatchesRemaining int       `json:"matches_remaining" validate:"min=0"`
+	Status           string    `json:"status" gorm:"default:'active'" validate:"oneof=active served cancelled"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Motivos de suspensão
+const (
+	SuspensionReasonRedCard            = "red_card"
+	SuspensionReasonSecondYellow       = "second_yellow"
+	SuspensionReasonYellowAccumulation = "yellow_accumulation"
+)
+
+// Status das suspensões
+const (
+	SuspensionStatusActive    = "active"
+	SuspensionStatusServed    = "served"
+	SuspensionStatusCancelled = "cancelled"
+)
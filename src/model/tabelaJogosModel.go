@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+)
+
+// FixtureGenerate contém os parâmetros para gerar a tabela de jogos de um torneio
+type FixtureGenerate struct {
+	Format string `json:"format" validate:"required,oneof=round_robin double_round_robin knockout"`
+	// Dias da semana em que pode haver rodada (padrão: sábado e domingo)
+	MatchDays []string `json:"match_days" validate:"omitempty,dive,oneof=sunday monday tuesday wednesday thursday friday saturday"`
+	// Horário de início das partidas no formato HH:MM (padrão: 16:00)
+	KickoffTime string `json:"kickoff_time" validate:"omitempty,len=5"`
+	// Duração reservada para cada partida, em minutos (padrão: 120)
+	MatchDuration int    `json:"match_duration" validate:"omitempty,min=90,max=240"`
+	Referee       string `json:"referee" validate:"omitempty,min=3,max=100"`
+	// Opções do mata-mata: jogos de ida e volta, final em jogo único e gols fora de casa
+	Legs           int  `json:"legs" validate:"omitempty,oneof=1 2"`
+	SingleLegFinal bool `json:"single_leg_final"`
+	AwayGoalsRule  bool `json:"away_goals_rule"`
+	// DryRun retorna a tabela sem gravar as partidas
+	DryRun bool `json:"dry_run"`
+}
+
+// FixtureRound é uma rodada da tabela gerada
+type FixtureRound struct {
+	Round   int             `json:"round"`
+	Leg     int             `json:"leg,omitempty"`
+	Date    time.Time       `json:"date"`
+	Matches []MatchResponse `json:"matches"`
+	// Times que folgam na rodada (número ímpar de times ou chaveamento com byes)
+	Byes []uint `json:"byes,omitempty"`
+}
+
+// FixtureResponse é a tabela de jogos gerada para um torneio
+type FixtureResponse struct {
+	TournamentID uint           `json:"tournament_id"`
+	Format       string         `json:"format"`
+	Teams        int            `json:"teams"`
+	Rounds       []FixtureRound `json:"rounds"`
+	DryRun       bool           `json:"dry_run"`
+	// Bracket traz o chaveamento criado quando o formato é mata-mata
+	Bracket *BracketResponse `json:"bracket,omitempty"`
+}
+
+// Formatos de tabela de jogos
+const (
+	FixtureFormatRoundRobin       = "round_robin"
+	FixtureFormatDoubleRoundRobin = "double_round_robin"
+	FixtureFormatKnockout         = "knockout"
+)
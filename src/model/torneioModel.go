@@ -15,8 +15,60 @@ type Tournament struct {
 	StartDate   time.Time `json:"start_date" gorm:"not null" validate:"required,future_date"`
 	EndDate     time.Time `json:"end_date" gorm:"not null" validate:"required,future_date"`
 	Status      string    `json:"status" gorm:"type:varchar(20);not null;default:'pending'" validate:"required,oneof=pending active completed cancelled"`
-	Teams       []Team    `json:"teams" gorm:"many2many:tournament_teams;"`
-	Matches     []Match   `json:"matches" gorm:"foreignKey:TournamentID"`
+	// Inscrição de times: MaxTeams 0 não limita a quantidade
+	MaxTeams             int        `json:"max_teams" gorm:"not null;default:0" validate:"omitempty,min=2,max=256"`
+	RegistrationDeadline *time.Time `json:"registration_deadline"`
+	// Regras da classificação
+	PointsWin   int    `json:"points_win" gorm:"not null;default:3" validate:"omitempty,min=0,max=10"`
+	PointsDraw  int    `json:"points_draw" gorm:"not null;default:1" validate:"omitempty,min=0,max=10"`
+	PointsLoss  int    `json:"points_loss" gorm:"not null;default:0" validate:"omitempty,min=0,max=10"`
+	TieBreakers string `json:"tie_breakers" gorm:"not null;default:'goal_difference,goals_scored,head_to_head'" validate:"omitempty,max=100"`
+	// Regras disciplinares: a cada YellowCardsForSuspension amarelos o jogador
+	// cumpre YellowSuspensionMatches partidas; um vermelho (ou segundo amarelo
+	// na mesma partida) gera RedCardSuspensionMatches partidas de suspensão
+	YellowCardsForSuspension int     `json:"yellow_cards_for_suspension" gorm:"not null;default:3" validate:"omitempty,min=1,max=10"`
+	YellowSuspensionMatches  int     `json:"yellow_suspension_matches" gorm:"not null;default:1" validate:"omitempty,min=1,max=10"`
+	RedCardSuspensionMatches int     `json:"red_card_suspension_matches" gorm:"not null;default:1" validate:"omitempty,min=1,max=10"`
+	Teams                    []Team  `json:"teams" gorm:"many2many:tournament_teams;"`
+	Matches                  []Match `json:"matches" gorm:"foreignKey:TournamentID"`
+}
+
+// TournamentCreate contém os dados de criação de um torneio. As pontuações
+// são ponteiros para que zero seja um valor válido: um zero no modelo seria
+// trocado pelo valor padrão da coluna.
+type TournamentCreate struct {
+	Name                     string     `json:"name" validate:"required,min=3,max=100"`
+	Description              string     `json:"description"`
+	StartDate                time.Time  `json:"start_date" validate:"required,future_date"`
+	EndDate                  time.Time  `json:"end_date" validate:"required,future_date"`
+	MaxTeams                 int        `json:"max_teams" validate:"omitempty,min=2,max=256"`
+	RegistrationDeadline     *time.Time `json:"registration_deadline"`
+	PointsWin                *int       `json:"points_win" validate:"omitempty,min=0,max=10"`
+	PointsDraw               *int       `json:"points_draw" validate:"omitempty,min=0,max=10"`
+	PointsLoss               *int       `json:"points_loss" validate:"omitempty,min=0,max=10"`
+	TieBreakers              string     `json:"tie_breakers" validate:"omitempty,max=100"`
+	YellowCardsForSuspension int        `json:"yellow_cards_for_suspension" validate:"omitempty,min=1,max=10"`
+	YellowSuspensionMatches  int        `json:"yellow_suspension_matches" validate:"omitempty,min=1,max=10"`
+	RedCardSuspensionMatches int        `json:"red_card_suspension_matches" validate:"omitempty,min=1,max=10"`
+}
+
+// TournamentUpdate contém os campos alteráveis de um torneio. As pontuações
+// são ponteiros para que zero seja um valor válido.
+type TournamentUpdate struct {
+	Name                     string     `json:"name" validate:"omitempty,min=3,max=100"`
+	Description              string     `json:"description"`
+	StartDate                time.Time  `json:"start_date"`
+	EndDate                  time.Time  `json:"end_date"`
+	Status                   string     `json:"status" validate:"omitempty,oneof=pending active completed cancelled"`
+	MaxTeams                 int        `json:"max_teams" validate:"omitempty,min=2,max=256"`
+	RegistrationDeadline     *time.Time `json:"registration_deadline"`
+	PointsWin                *int       `json:"points_win" validate:"omitempty,min=0,max=10"`
+	PointsDraw               *int       `json:"points_draw" validate:"omitempty,min=0,max=10"`
+	PointsLoss               *int       `json:"points_loss" validate:"omitempty,min=0,max=10"`
+	TieBreakers              string     `json:"tie_breakers" validate:"omitempty,max=100"`
+	YellowCardsForSuspension int        `json:"yellow_cards_for_suspension" validate:"omitempty,min=1,max=10"`
+	YellowSuspensionMatches  int        `json:"yellow_suspension_matches" validate:"omitempty,min=1,max=10"`
+	RedCardSuspensionMatches int        `json:"red_card_suspension_matches" validate:"omitempty,min=1,max=10"`
 }
 
 // TableName especifica o nome da tabela no banco de dados
@@ -50,3 +102,26 @@ type TournamentTeam struct {
 func (TournamentTeam) TableName() string {
 	return "tournament_teams"
 }
+
+// TournamentTeamRegister contém o time a ser inscrito no torneio
+type TournamentTeamRegister struct {
+	TeamID uint `json:"team_id" validate:"required"`
+}
+
+// TournamentTeamResponse é um time inscrito no torneio
+type TournamentTeamResponse struct {
+	TeamID   uint      `json:"team_id"`
+	Name     string    `json:"name"`
+	Country  string    `json:"country"`
+	City     string    `json:"city"`
+	Stadium  string    `json:"stadium"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// Critérios de desempate da classificação
+const (
+	TieBreakerHeadToHead     = "head_to_head"
+	TieBreakerGoalDifference = "goal_difference"
+	TieBreakerGoalsScored    = "goals_scored"
+	TieBreakerWins           = "wins"
+)
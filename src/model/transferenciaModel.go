@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+)
+
+// PlayerSpell é um período em que o jogador pertenceu a um time. O período
+// vale de StartDate (inclusive) até EndDate (exclusive); StartDate nulo indica
+// o vínculo original, anterior ao registro de transferências, e EndDate nulo
+// indica o vínculo atual.
+type PlayerSpell struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	PlayerID  uint       `json:"player_id" gorm:"index" validate:"required"`
+	TeamID    uint       `json:"team_id" gorm:"index" validate:"required"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+	// FromTeamID e Fee descrevem a transferência que iniciou o período
+	FromTeamID *uint     `json:"from_team_id"`
+	Fee        float64   `json:"fee" gorm:"not null;default:0" validate:"min=0"`
+	Notes      string    `json:"notes" validate:"omitempty,max=500"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// PlayerTransferCreate registra a transferência de um jogador para outro time
+type PlayerTransferCreate struct {
+	ToTeamID uint      `json:"to_team_id" validate:"required"`
+	Date     time.Time `json:"date" validate:"required"`
+	// Number é a nova camisa; se omitido, o jogador mantém o número atual
+	Number int     `json:"number" validate:"omitempty,min=1,max=99"`
+	Fee    float64 `json:"fee" validate:"omitempty,min=0"`
+	Notes  string  `json:"notes" validate:"omitempty,max=500"`
+}
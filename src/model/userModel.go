@@ -5,15 +5,22 @@ import (
 )
 
 type User struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Name      string    `json:"name" validate:"required,min=3,max=100"`
-	Email     string    `json:"email" validate:"required,email"`
-	Password  string    `json:"-" validate:"required,min=8,strong_password"`
-	Role      string    `json:"role" validate:"required,oneof=user admin"`
-	Balance   float64   `json:"balance" validate:"required,min=0"`
-	Status    string    `json:"status" validate:"required,oneof=active inactive blocked"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID       uint    `json:"id" gorm:"primaryKey"`
+	Name     string  `json:"name" validate:"required,min=3,max=100"`
+	Email    string  `json:"email" validate:"required,email"`
+	Password string  `json:"-" validate:"required,min=8,strong_password"`
+	Role     string  `json:"role" validate:"required,oneof=user admin"`
+	Balance  float64 `json:"balance" validate:"required,min=0"`
+	Status   string  `json:"status" validate:"required,oneof=active inactive blocked"`
+	// StakeFactor multiplica os limites de aposta do usuário (1 = limite padrão)
+	StakeFactor float64   `json:"stake_factor" gorm:"not null;default:1" validate:"omitempty,min=0,max=10"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// UserStakeFactor altera o fator de limite de aposta de um usuário
+type UserStakeFactor struct {
+	StakeFactor *float64 `json:"stake_factor" validate:"required,min=0,max=10"`
 }
 
 type UserCreate struct {
@@ -25,12 +32,11 @@ type UserCreate struct {
 }
 
 type UserUpdate struct {
-	Name     string  `json:"name" validate:"omitempty,min=3,max=100"`
-	Email    string  `json:"email" validate:"omitempty,email"`
-	Password string  `json:"password" validate:"omitempty,min=8,strong_password"`
-	Role     string  `json:"role" validate:"omitempty,oneof=user admin"`
-	Balance  float64 `json:"balance" validate:"omitempty,min=0"`
-	Status   string  `json:"status" validate:"omitempty,oneof=active inactive blocked"`
+	Name     string `json:"name" validate:"omitempty,min=3,max=100"`
+	Email    string `json:"email" validate:"omitempty,email"`
+	Password string `json:"password" validate:"omitempty,min=8,strong_password"`
+	Role     string `json:"role" validate:"omitempty,oneof=user admin"`
+	Status   string `json:"status" validate:"omitempty,oneof=active inactive blocked"`
 }
 
 type UserResponse struct {
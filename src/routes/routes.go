@@ -19,8 +19,19 @@ func SetupRouter(r *gin.Engine, cache util.Cache, db *gorm.DB, promotionControll
 	cacheMiddleware := middleware.NewCacheMiddleware(cache)
 
 	// Inicialização dos controllers
-	tournamentController := controller.NewTournamentController(db)
-	matchEventController := controller.NewMatchEventController(db)
+	tournamentController := controller.NewTournamentController(db, cache)
+	matchEventController := controller.NewMatchEventController(db, cache)
+	matchStatisticsController := controller.NewMatchStatisticsController(db)
+	matchLifecycleController := controller.NewMatchLifecycleController(db, cache)
+	matchLineupController := controller.NewMatchLineupController(db)
+	notificationController := controller.NewNotificationController(db)
+	oddsController := controller.NewOddsController(db)
+	riskController := controller.NewRiskController(db)
+	stakeLimitController := controller.NewStakeLimitController(db)
+	analyticsController := controller.NewAnalyticsController(db, cache)
+	controller.SetAnalyticsCache(cache)
+	reportController := controller.NewReportController(db)
+	backOfficeController := controller.NewBackOfficeController(db)
 
 	// Rotas públicas
 	r.POST("/auth/login", controller.Login)
@@ -35,6 +46,7 @@ func SetupRouter(r *gin.Engine, cache util.Cache, db *gorm.DB, promotionControll
 		{
 			users.GET("/", cacheMiddleware.CacheGet(5*time.Minute), controller.ListUsers)
 			users.GET("/:id", cacheMiddleware.CacheGetWithKey(util.UserCacheKey, 5*time.Minute), controller.GetUser)
+			users.GET("/me/analytics", analyticsController.GetMyAnalytics)
 			users.PUT("/:id", cacheMiddleware.InvalidateCache(util.UserCacheKey), controller.UpdateUser)
 			users.DELETE("/:id", cacheMiddleware.InvalidateCache(util.UserCacheKey), controller.DeleteUser)
 		}
@@ -49,6 +61,20 @@ func SetupRouter(r *gin.Engine, cache util.Cache, db *gorm.DB, promotionControll
 			teams.DELETE("/:id", cacheMiddleware.InvalidateCache(util.TeamCacheKey), controller.DeleteTeam)
 		}
 
+		// Rotas de jogadores
+		players := auth.Group("/players")
+		{
+			players.POST("/", cacheMiddleware.InvalidateCache(util.PlayersCacheKey), controller.CreatePlayer)
+			players.GET("/", cacheMiddleware.CacheGet(util.PlayerCacheExpiry), controller.ListPlayers)
+			players.GET("/:id", cacheMiddleware.CacheGetWithKey(util.PlayerCacheKey, util.PlayerCacheExpiry), controller.GetPlayer)
+			players.GET("/:id/stats", controller.GetPlayerStats)
+			players.GET("/:id/suspensions", controller.ListPlayerSuspensions)
+			players.GET("/:id/transfers", controller.ListPlayerTransfers)
+			players.POST("/:id/transfers", cacheMiddleware.InvalidateCache(util.PlayerCacheKey), controller.TransferPlayer)
+			players.PUT("/:id", cacheMiddleware.InvalidateCache(util.PlayerCacheKey), controller.UpdatePlayer)
+			players.DELETE("/:id", cacheMiddleware.InvalidateCache(util.PlayerCacheKey), controller.DeletePlayer)
+		}
+
 		// Rotas de campeonatos
 		tournaments := auth.Group("/tournaments")
 		{
@@ -57,6 +83,16 @@ func SetupRouter(r *gin.Engine, cache util.Cache, db *gorm.DB, promotionControll
 			tournaments.GET("/:id", cacheMiddleware.CacheGetWithKey(util.TournamentCacheKey, util.TournamentCacheExpiry), tournamentController.GetTournament)
 			tournaments.PUT("/:id", cacheMiddleware.InvalidateCache(util.TournamentCacheKey), tournamentController.UpdateTournament)
 			tournaments.DELETE("/:id", cacheMiddleware.InvalidateCache(util.TournamentCacheKey), tournamentController.DeleteTournament)
+			tournaments.GET("/:id/standings", tournamentController.GetStandings)
+			tournaments.POST("/:id/fixtures/generate", middleware.AdminMiddleware(), tournamentController.GenerateFixtures)
+			tournaments.GET("/:id/bracket", tournamentController.GetBracket)
+			tournaments.POST("/:id/bracket/ties/:tie_id/replay", middleware.AdminMiddleware(), tournamentController.ReplayTieMatch)
+			tournaments.POST("/:id/bracket/ties/:tie_id/decide", middleware.AdminMiddleware(), tournamentController.DecideTie)
+			tournaments.GET("/:id/top-scorers", tournamentController.GetTopScorers)
+			tournaments.GET("/:id/discipline", tournamentController.GetDiscipline)
+			tournaments.GET("/:id/teams", tournamentController.ListTournamentTeams)
+			tournaments.POST("/:id/teams", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.TournamentCacheKey), tournamentController.RegisterTournamentTeam)
+			tournaments.DELETE("/:id/teams/:team_id", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.TournamentCacheKey), tournamentController.UnregisterTournamentTeam)
 		}
 
 		// Rotas de apostas
@@ -64,6 +100,12 @@ func SetupRouter(r *gin.Engine, cache util.Cache, db *gorm.DB, promotionControll
 		{
 			bets.POST("/", cacheMiddleware.InvalidateCache(util.UserBetsCacheKey), controller.CreateBet)
 			bets.GET("/", cacheMiddleware.CacheGetWithKey(util.UserBetsCacheKey, util.BetCacheExpiry), controller.ListUserBets)
+			bets.POST("/builder/quote", controller.QuoteBetBuilder)
+			bets.POST("/builder", cacheMiddleware.InvalidateCache(util.UserBetsCacheKey), controller.CreateBuilderBet)
+			bets.POST("/system", cacheMiddleware.InvalidateCache(util.UserBetsCacheKey), controller.CreateSystemBet)
+			bets.GET("/system", controller.ListSystemBets)
+			bets.GET("/system/:id", controller.GetSystemBet)
+			bets.GET("/export", controller.ExportBets)
 			bets.DELETE("/:id", cacheMiddleware.InvalidateCache(util.BetCacheKey), controller.CancelBet)
 		}
 
@@ -73,8 +115,41 @@ func SetupRouter(r *gin.Engine, cache util.Cache, db *gorm.DB, promotionControll
 			matches.POST("/", cacheMiddleware.InvalidateCache(util.MatchesCacheKey), controller.CreateMatch)
 			matches.GET("/", cacheMiddleware.CacheGet(util.MatchCacheExpiry), controller.ListMatches)
 			matches.GET("/:id", cacheMiddleware.CacheGetWithKey(util.MatchCacheKey, util.MatchCacheExpiry), controller.GetMatch)
-			matches.PUT("/:id", cacheMiddleware.InvalidateCache(util.MatchCacheKey), controller.UpdateMatch)
-			matches.DELETE("/:id", cacheMiddleware.InvalidateCache(util.MatchCacheKey), controller.DeleteMatch)
+
+			// A edição altera o placar usado na liquidação e a exclusão anula as
+			// apostas: as duas ficam com os administradores
+			matches.PUT("/:id", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchCacheKey), controller.UpdateMatch)
+			matches.DELETE("/:id", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchCacheKey), controller.DeleteMatch)
+
+			// Transições de status da partida: restritas aos administradores, pois
+			// liquidam e anulam apostas
+			matches.POST("/:id/kickoff", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchCacheKey), matchLifecycleController.Kickoff)
+			matches.POST("/:id/half-time", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchCacheKey), matchLifecycleController.HalfTime)
+			matches.POST("/:id/second-half", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchCacheKey), matchLifecycleController.SecondHalf)
+			matches.POST("/:id/finish", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchCacheKey), matchLifecycleController.Finish)
+			matches.POST("/:id/postpone", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchCacheKey), matchLifecycleController.Postpone)
+			matches.POST("/:id/reschedule", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchCacheKey), matchLifecycleController.Reschedule)
+			matches.POST("/:id/cancel", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchCacheKey), matchLifecycleController.Cancel)
+			matches.POST("/:id/abandon", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchCacheKey), matchLifecycleController.Abandon)
+
+			// Estatísticas da partida: a gravação liquida apostas de escanteios e
+			// cartões, por isso fica com os administradores
+			matches.GET("/:id/statistics", cacheMiddleware.CacheGetWithKey(util.MatchStatsCacheKey, util.MatchStatsCacheExpiry), matchStatisticsController.GetStatistics)
+			matches.PUT("/:id/statistics", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchStatsCacheKey), matchStatisticsController.UpsertStatistics)
+			matches.PATCH("/:id/statistics", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchStatsCacheKey), matchStatisticsController.UpdateStatistics)
+
+			// Cotações: o cálculo e a revisão ficam com os administradores (traders)
+			matches.GET("/:id/odds", oddsController.ListOdds)
+			matches.POST("/:id/odds/compile", middleware.AdminMiddleware(), oddsController.CompileOdds)
+			matches.POST("/:id/odds/approve", middleware.AdminMiddleware(), oddsController.ApproveOdds)
+			matches.POST("/:id/odds/reject", middleware.AdminMiddleware(), oddsController.RejectOdds)
+
+			// Linha do tempo
+			matches.GET("/:id/timeline", matchEventController.GetTimeline)
+
+			// Escalações
+			matches.GET("/:id/lineups", matchLineupController.GetLineups)
+			matches.PUT("/:id/lineups", middleware.AdminMiddleware(), matchLineupController.SubmitLineup)
 		}
 
 		// Rotas de times em partidas
@@ -85,14 +160,52 @@ func SetupRouter(r *gin.Engine, cache util.Cache, db *gorm.DB, promotionControll
 			matchTeams.DELETE("/:id", cacheMiddleware.InvalidateCache(util.MatchCacheKey), controller.DeletePartidaClube)
 		}
 
-		// Rotas de eventos da partida
+		// Rotas de eventos da partida: as alterações corrigem placares e reliquidam
+		// apostas, por isso ficam com os administradores
 		matchEvents := auth.Group("/match-events")
 		{
-			matchEvents.POST("/", cacheMiddleware.InvalidateCache(util.MatchCacheKey), matchEventController.CreateEvent)
+			matchEvents.POST("/", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchCacheKey), matchEventController.CreateEvent)
 			matchEvents.GET("/", cacheMiddleware.CacheGet(util.MatchCacheExpiry), matchEventController.ListEvents)
 			matchEvents.GET("/:id", cacheMiddleware.CacheGetWithKey(util.MatchCacheKey, util.MatchCacheExpiry), matchEventController.GetEvent)
-			matchEvents.PUT("/:id", cacheMiddleware.InvalidateCache(util.MatchCacheKey), matchEventController.UpdateEvent)
-			matchEvents.DELETE("/:id", cacheMiddleware.InvalidateCache(util.MatchCacheKey), matchEventController.DeleteEvent)
+			matchEvents.PUT("/:id", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchCacheKey), matchEventController.UpdateEvent)
+			matchEvents.DELETE("/:id", middleware.AdminMiddleware(), cacheMiddleware.InvalidateCache(util.MatchCacheKey), matchEventController.DeleteEvent)
+			matchEvents.GET("/:id/revisions", matchEventController.ListEventRevisions)
+		}
+
+		// Rotas de notificações do usuário
+		notifications := auth.Group("/notifications")
+		{
+			notifications.GET("/", notificationController.ListNotifications)
+			notifications.PUT("/:id/read", notificationController.MarkAsRead)
+		}
+
+		// Rotas administrativas
+		admin := auth.Group("/admin")
+		admin.Use(middleware.AdminMiddleware())
+		{
+			// Exposição e alertas de risco
+			admin.GET("/risk/matches/:id", riskController.GetMatchRisk)
+			admin.GET("/risk/alerts", riskController.ListAlerts)
+			admin.POST("/risk/alerts/:id/ack", riskController.AcknowledgeAlert)
+
+			// Limites de aposta e de prêmio
+			admin.GET("/stake-limits", stakeLimitController.ListStakeLimits)
+			admin.POST("/stake-limits", stakeLimitController.CreateStakeLimit)
+			admin.PUT("/stake-limits/:id", stakeLimitController.UpdateStakeLimit)
+			admin.DELETE("/stake-limits/:id", stakeLimitController.DeleteStakeLimit)
+			admin.PUT("/users/:id/stake-factor", cacheMiddleware.InvalidateCache(util.UserCacheKey), stakeLimitController.SetUserStakeFactor)
+
+			// Extrato de apostas de um usuário, para o suporte
+			admin.GET("/users/:id/bets/export", controller.ExportUserBets)
+
+			// Relatórios financeiros
+			admin.GET("/reports/financial", reportController.GetFinancialReport)
+
+			// Intervenções manuais do suporte, registradas na trilha de auditoria
+			admin.POST("/bets/:id/void", cacheMiddleware.InvalidateCache(util.UserCacheKey), backOfficeController.VoidBet)
+			admin.POST("/bets/:id/settle", cacheMiddleware.InvalidateCache(util.UserCacheKey), backOfficeController.SettleBet)
+			admin.POST("/users/:id/balance-adjustments", cacheMiddleware.InvalidateCache(util.UserCacheKey), backOfficeController.AdjustBalance)
+			admin.GET("/audit-logs", backOfficeController.ListAuditLogs)
 		}
 
 		// Rotas de promoções
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"errors"
 	"time"
 )
 
@@ -13,13 +14,17 @@ type Cache interface {
 	Close()
 }
 
+// ErrCacheMiss indica que a chave não está no cache ou expirou
+var ErrCacheMiss = errors.New("chave não encontrada no cache")
+
 // NewCache cria uma nova instância de cache
 func NewCache() Cache {
 	// Retorna um cache dummy ou baseado em memória se não usar Redis
 	return &DummyCache{}
 }
 
-// DummyCache é uma implementação de cache que não faz nada
+// DummyCache é uma implementação de cache que não guarda nada. Get sempre
+// informa ausência, para que os chamadores consultem o banco.
 type DummyCache struct{}
 
 func (d *DummyCache) Set(key string, value interface{}, expiration time.Duration) error {
@@ -27,7 +32,7 @@ func (d *DummyCache) Set(key string, value interface{}, expiration time.Duration
 }
 
 func (d *DummyCache) Get(key string, dest interface{}) error {
-	return nil
+	return ErrCacheMiss
 }
 
 func (d *DummyCache) Delete(key string) error {
@@ -49,10 +54,13 @@ const (
 	TeamCacheKey         = "team:"
 	TournamentsCacheKey  = "tournaments:"
 	TournamentCacheKey   = "tournament:"
+	StandingsCacheKey    = "standings:"
 	MatchesCacheKey      = "matches:"
 	MatchCacheKey        = "match:"
+	MatchStatsCacheKey   = "match_stats:"
 	UserBetsCacheKey     = "user_bets:"
 	BetCacheKey          = "bet:"
+	PlayersCacheKey      = "players:"
 	PlayerCacheKey       = "player:"
 	GoalCacheKey         = "goal:"
 	CardCacheKey         = "card:"
@@ -62,6 +70,7 @@ const (
 	CornerCacheKey       = "corner:"
 	PromotionsCacheKey   = "promotions:"
 	PromotionCacheKey    = "promotion:"
+	AnalyticsCacheKey    = "user_analytics:"
 )
 
 // Constantes para expiração do cache
@@ -69,7 +78,9 @@ const (
 	UserCacheExpiry         = 5 * time.Minute
 	TeamCacheExpiry         = 10 * time.Minute
 	TournamentCacheExpiry   = 15 * time.Minute
+	StandingsCacheExpiry    = 10 * time.Minute
 	MatchCacheExpiry        = 30 * time.Minute
+	MatchStatsCacheExpiry   = 1 * time.Minute
 	BetCacheExpiry          = 5 * time.Minute
 	PlayerCacheExpiry       = 10 * time.Minute
 	GoalCacheExpiry         = 5 * time.Minute
@@ -79,4 +90,5 @@ const (
 	ThrowInCacheExpiry      = 5 * time.Minute
 	CornerCacheExpiry       = 5 * time.Minute
 	PromotionCacheExpiry    = 5 * time.Minute
+	AnalyticsCacheExpiry    = 10 * time.Minute
 )
@@ -0,0 +1,134 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Fontes disponíveis no PDF (fontes padrão, que não precisam ser embutidas)
+const (
+	PDFFontRegular = "F1"
+	PDFFontBold    = "F2"
+	PDFFontMono    = "F3"
+)
+
+// Dimensões da página A4 em pontos
+const (
+	pdfPageWidth    = 595.0
+	pdfPageHeight   = 842.0
+	pdfMargin       = 40.0
+	pdfFooterHeight = 30.0
+)
+
+type pdfText struct {
+	font string
+	size float64
+	x, y float64
+	text string
+}
+
+// PDFDocument monta um PDF simples, só de texto, em páginas A4. Atende
+// extratos e relatórios sem depender de bibliotecas externas; as linhas que
+// não cabem na página passam para a seguinte.
+type PDFDocument struct {
+	pages [][]pdfText
+	y     float64
+}
+
+// NewPDFDocument cria um documento com a primeira página vazia
+func NewPDFDocument() *PDFDocument {
+	d := &PDFDocument{}
+	d.newPage()
+	return d
+}
+
+func (d *PDFDocument) newPage() {
+	d.pages = append(d.pages, nil)
+	d.y = pdfPageHeight - pdfMargin
+}
+
+// Text escreve uma linha de texto com a fonte e o tamanho informados
+func (d *PDFDocument) Text(font string, size float64, text string) {
+	height := size * 1.4
+	if d.y-height < pdfMargin+pdfFooterHeight {
+		d.newPage()
+	}
+	d.y -= height
+	last := len(d.pages) - 1
+	d.pages[last] = append(d.pages[last], pdfText{font: font, size: size, x: pdfMargin, y: d.y, text: text})
+}
+
+// Space avança verticalmente sem escrever
+func (d *PDFDocument) Space(height float64) {
+	d.y -= height
+}
+
+// pdfString converte o texto para WinAnsi (Latin-1) e escapa os caracteres
+// especiais das strings do PDF
+func pdfString(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r == '\t':
+			b.WriteByte(' ')
+		case r < 32:
+		case r < 256:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// WriteTo gera o PDF, com o número da página no rodapé de cada página
+func (d *PDFDocument) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: catálogo, 2: árvore de páginas, 3 a 5: fontes, depois página e conteúdo
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, texts := range d.pages {
+		footer := pdfText{font: PDFFontRegular, size: 8, x: pdfMargin, y: pdfMargin / 2,
+			text: fmt.Sprintf("Página %d de %d", i+1, len(d.pages))}
+		var content strings.Builder
+		for _, t := range append(texts, footer) {
+			fmt.Fprintf(&content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", t.font, t.size, t.x, t.y, pdfString(t.text))
+		}
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}